@@ -0,0 +1,337 @@
+// Command redisorm-gen emits typed field references for redisorm models, so
+// query field names are checked by the compiler:
+//
+//	//go:generate go run github.com/manojoshi/redisorm/cmd/redisorm-gen -type Order
+//
+//	type Order struct {
+//	    Status string `redisorm:"@status,TAG"`
+//	    Qty    int    `redisorm:"@qty,NUMERIC"`
+//	}
+//
+// writes redisorm_fields.go next to it with
+//
+//	var OrderFields = struct {
+//	    Status q.TagField
+//	    Qty    q.NumericField
+//	}{Status: "status", Qty: "qty"}
+//
+// and queries read q.And(OrderFields.Status.Eq("PENDING"),
+// OrderFields.Qty.Between(1, 5)).  Field types follow index.AutoCreate:
+// the TAG / NUMERIC / GEO / GEOSHAPE / VECTOR attribute, time.Time as
+// NUMERIC, []string as TAG, TEXT otherwise; SHADOW fields get the NUMERIC
+// "<name>_num" sibling.  Embedded fragments are flattened and nested ones
+// prefixed (Audit.CreatedTS ➜ AuditCreatedTS), as scan.Fields does – for
+// fragment types declared in the same package, the only ones it can see.
+//
+// Without -type every struct with a redisorm tag is generated.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/manojoshi/redisorm/index"
+	"github.com/manojoshi/redisorm/scan"
+)
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("redisorm-gen: ")
+	types := flag.String("type", "", "comma-separated model types (default: every tagged struct)")
+	output := flag.String("output", "redisorm_fields.go", "output file, relative to the package directory")
+	flag.Parse()
+	dir := "."
+	if flag.NArg() > 0 {
+		dir = flag.Arg(0)
+	}
+	out := *output
+	if !filepath.IsAbs(out) {
+		out = filepath.Join(dir, out)
+	}
+
+	pkg, err := load(dir, out)
+	if err != nil {
+		log.Fatal(err)
+	}
+	var names []string
+	if *types != "" {
+		names = strings.Split(*types, ",")
+	}
+	src, err := pkg.generate(names)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(out, src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// pkg is the parsed package the models live in.
+type pkg struct {
+	name    string
+	structs map[string]*ast.StructType // by type name
+	order   []string                   // struct names in source order
+	coded   map[string]bool            // types with MarshalText: stored as one value
+}
+
+// load parses the non-test Go files of dir, except skip (the previous
+// output).
+func load(dir, skip string) (*pkg, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	p := &pkg{structs: map[string]*ast.StructType{}, coded: map[string]bool{}}
+	fset := token.NewFileSet()
+	for _, e := range entries {
+		path := filepath.Join(dir, e.Name())
+		if e.IsDir() || !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") || sameFile(path, skip) {
+			continue
+		}
+		f, err := parser.ParseFile(fset, path, nil, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, err
+		}
+		p.name = f.Name.Name
+		ast.Inspect(f, func(n ast.Node) bool {
+			switch d := n.(type) {
+			case *ast.TypeSpec:
+				if st, ok := d.Type.(*ast.StructType); ok {
+					p.structs[d.Name.Name] = st
+					p.order = append(p.order, d.Name.Name)
+				}
+			case *ast.FuncDecl:
+				if d.Recv != nil && d.Name.Name == "MarshalText" {
+					p.coded[typeName(d.Recv.List[0].Type)] = true
+				}
+				return false
+			}
+			return true
+		})
+	}
+	if p.name == "" {
+		return nil, fmt.Errorf("no Go files in %s", dir)
+	}
+	return p, nil
+}
+
+func sameFile(a, b string) bool {
+	x, err1 := filepath.Abs(a)
+	y, err2 := filepath.Abs(b)
+	return err1 == nil && err2 == nil && x == y
+}
+
+// field is one generated field reference.
+type field struct {
+	goName string // AuditCreatedTS
+	name   string // audit_created_ts
+	kind   string // TagField, NumericField, …
+}
+
+// generate renders the references of the named models, or of every
+// tagged struct when names is empty.
+func (p *pkg) generate(names []string) ([]byte, error) {
+	if len(names) == 0 {
+		for _, n := range p.order {
+			if p.tagged(p.structs[n]) {
+				names = append(names, n)
+			}
+		}
+		if len(names) == 0 {
+			return nil, fmt.Errorf("no struct with redisorm tags in package %s", p.name)
+		}
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by redisorm-gen; DO NOT EDIT.\n\n")
+	fmt.Fprintf(&buf, "package %s\n\n", p.name)
+	fmt.Fprintf(&buf, "import q %q\n", "github.com/manojoshi/redisorm/query")
+	for _, n := range names {
+		st, ok := p.structs[strings.TrimSpace(n)]
+		if !ok {
+			return nil, fmt.Errorf("struct type %s not found in package %s", n, p.name)
+		}
+		n = strings.TrimSpace(n)
+		fields := p.fields(st, "", "", nil)
+		if len(fields) == 0 {
+			return nil, fmt.Errorf("%s has no redisorm-tagged fields", n)
+		}
+		if err := unique(n, fields); err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(&buf, "\n// %sFields are the typed query fields of %s.\n", n, n)
+		fmt.Fprintf(&buf, "var %sFields = struct {\n", n)
+		for _, f := range fields {
+			fmt.Fprintf(&buf, "\t%s q.%s\n", f.goName, f.kind)
+		}
+		buf.WriteString("}{\n")
+		for _, f := range fields {
+			fmt.Fprintf(&buf, "\t%s: %q,\n", f.goName, f.name)
+		}
+		buf.WriteString("}\n")
+	}
+	return format.Source(buf.Bytes())
+}
+
+// tagged reports whether st has a redisorm tag of its own.
+func (p *pkg) tagged(st *ast.StructType) bool {
+	for _, f := range st.Fields.List {
+		if tag := redisormTag(f); tag != "" && tag != "-" {
+			return true
+		}
+	}
+	return false
+}
+
+// fields mirrors scan.Fields on the syntax tree: goPrefix and prefix name
+// the nested fragment being walked, seen guards against recursive types.
+func (p *pkg) fields(st *ast.StructType, goPrefix, prefix string, seen []string) []field {
+	var out []field
+	for _, f := range st.Fields.List {
+		tag := redisormTag(f)
+		ft := f.Type
+		if s, ok := ft.(*ast.StarExpr); ok {
+			ft = s.X // *T fields index like T; *Fragment is not descended into below
+		}
+		frag, fragName := p.fragment(f.Type)
+		switch {
+		case tag == "" && len(f.Names) == 0 && frag != nil && !slices.Contains(seen, fragName):
+			out = append(out, p.fields(frag, goPrefix, prefix, append(seen, fragName))...)
+			continue
+		case tag == "" && len(f.Names) == 0 && isSelector(f.Type):
+			log.Printf("embedded %s is declared in another package; its fields are skipped", typeName(f.Type))
+			continue
+		case tag == "" || tag == "-":
+			continue
+		}
+		parts := strings.Split(tag, ",")
+		name := strings.TrimPrefix(parts[0], "@")
+		goName := fieldName(f)
+		if frag != nil && len(parts) == 1 && !slices.Contains(seen, fragName) {
+			out = append(out, p.fields(frag, goPrefix+goName, prefix+name+"_", append(seen, fragName))...)
+			continue
+		}
+		if scan.MetaField(name) {
+			continue // reply metadata, not an indexed field
+		}
+		fd := field{goName: goPrefix + goName, name: prefix + name, kind: kindOf(ft, parts[1:])}
+		if fd.kind == "" { // SHADOW
+			fd.name += index.ShadowSuffix
+			fd.kind = "NumericField"
+		}
+		out = append(out, fd)
+	}
+	return out
+}
+
+// fragment returns the struct declaration of t when it is a struct type of
+// this package stored field by field, not through a codec.
+func (p *pkg) fragment(t ast.Expr) (*ast.StructType, string) {
+	id, ok := t.(*ast.Ident)
+	if !ok || p.coded[id.Name] {
+		return nil, ""
+	}
+	return p.structs[id.Name], id.Name
+}
+
+// kindOf picks the handle type the way index.BuildSchema picks the field
+// type; "" means SHADOW.
+func kindOf(t ast.Expr, attrs []string) string {
+	kind := "TextField"
+	if sel, ok := t.(*ast.SelectorExpr); ok && sel.Sel.Name == "Time" {
+		if pk, ok := sel.X.(*ast.Ident); ok && pk.Name == "time" {
+			kind = "NumericField"
+		}
+	}
+	if arr, ok := t.(*ast.ArrayType); ok && arr.Len == nil {
+		if el, ok := arr.Elt.(*ast.Ident); ok && el.Name == "string" {
+			kind = "TagField"
+		}
+	}
+	for _, a := range attrs {
+		switch strings.ToUpper(a) {
+		case "TAG":
+			kind = "TagField"
+		case "NUMERIC":
+			kind = "NumericField"
+		case "GEO":
+			kind = "GeoField"
+		case "GEOSHAPE":
+			kind = "GeoShapeField"
+		case "VECTOR":
+			kind = "VectorField"
+		case "SHADOW":
+			return ""
+		}
+	}
+	return kind
+}
+
+// unique rejects models whose flattened Go names collide, which the
+// generated struct could not hold.
+func unique(model string, fields []field) error {
+	seen := map[string]bool{}
+	var dup []string
+	for _, f := range fields {
+		if seen[f.goName] {
+			dup = append(dup, f.goName)
+		}
+		seen[f.goName] = true
+	}
+	if len(dup) > 0 {
+		sort.Strings(dup)
+		return fmt.Errorf("%s: duplicate field names %s", model, strings.Join(dup, ", "))
+	}
+	return nil
+}
+
+func redisormTag(f *ast.Field) string {
+	if f.Tag == nil {
+		return ""
+	}
+	s, err := strconv.Unquote(f.Tag.Value)
+	if err != nil {
+		return ""
+	}
+	return reflect.StructTag(s).Get("redisorm")
+}
+
+// fieldName is the Go name of f; for an embedded field, its type name.
+func fieldName(f *ast.Field) string {
+	if len(f.Names) > 0 {
+		return f.Names[0].Name
+	}
+	return typeName(f.Type)
+}
+
+func isSelector(t ast.Expr) bool {
+	_, ok := t.(*ast.SelectorExpr)
+	return ok
+}
+
+func typeName(t ast.Expr) string {
+	switch x := t.(type) {
+	case *ast.StarExpr:
+		return typeName(x.X)
+	case *ast.SelectorExpr:
+		return x.Sel.Name
+	case *ast.Ident:
+		return x.Name
+	case *ast.IndexExpr:
+		return typeName(x.X)
+	}
+	return ""
+}
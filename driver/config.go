@@ -0,0 +1,70 @@
+package driver
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+)
+
+// ConfigGet reads search module settings (FT.CONFIG GET).  option may be a
+// single name such as "MAXPREFIXEXPANSIONS" or "*" for all of them.
+func ConfigGet(ctx context.Context, exec Executor, option string) (map[string]string, error) {
+	args := []interface{}{"FT.CONFIG", "GET", option}
+	raw, err := exec.Do(ctx, args...)
+	if err != nil {
+		return nil, Wrap(args, err)
+	}
+	out := map[string]string{}
+	switch v := raw.(type) {
+	case []interface{}: // RESP-2: [[name, value], …]
+		for _, pair := range v {
+			kv, ok := pair.([]interface{})
+			if !ok || len(kv) != 2 {
+				return nil, fmt.Errorf("driver: unexpected FT.CONFIG reply entry %T", pair)
+			}
+			out[toString(kv[0])] = configValue(kv[1])
+		}
+	case map[interface{}]interface{}: // RESP-3
+		for k, val := range v {
+			out[toString(k)] = configValue(val)
+		}
+	case map[string]interface{}:
+		for k, val := range v {
+			out[k] = configValue(val)
+		}
+	default:
+		return nil, fmt.Errorf("driver: unexpected FT.CONFIG reply %T", raw)
+	}
+	return out, nil
+}
+
+// ConfigSet changes a search module setting at runtime (FT.CONFIG SET).
+func ConfigSet(ctx context.Context, exec Executor, option string, value any) error {
+	args := []interface{}{"FT.CONFIG", "SET", option, value}
+	if _, err := exec.Do(ctx, args...); err != nil {
+		return Wrap(args, err)
+	}
+	return nil
+}
+
+// MaxPrefixExpansions returns the server's MAXPREFIXEXPANSIONS: how many
+// terms a prefix, wildcard or fuzzy query may expand to before RediSearch
+// silently stops expanding and returns partial results.
+func MaxPrefixExpansions(ctx context.Context, exec Executor) (int, error) {
+	cfg, err := ConfigGet(ctx, exec, "MAXPREFIXEXPANSIONS")
+	if err != nil {
+		return 0, err
+	}
+	v, ok := cfg["MAXPREFIXEXPANSIONS"]
+	if !ok {
+		return 0, fmt.Errorf("driver: MAXPREFIXEXPANSIONS not reported by server")
+	}
+	return strconv.Atoi(v)
+}
+
+func configValue(v interface{}) string {
+	if v == nil {
+		return ""
+	}
+	return toString(v)
+}
@@ -0,0 +1,48 @@
+package driver
+
+import (
+	"context"
+	"errors"
+	"strconv"
+)
+
+// CursorRead sends FT.CURSOR READ through any Executor and returns the page –
+// decode it like an FT.AGGREGATE reply – and the next cursor id, 0 once the
+// cursor is exhausted.
+func CursorRead(ctx context.Context, exec Executor, index string, cursor uint64, count int) (any, uint64, error) {
+	if cursor == 0 {
+		return nil, 0, errors.New("driver: cursor id must be > 0")
+	}
+	args := []interface{}{"FT.CURSOR", "READ", index, cursor}
+	if count > 0 {
+		args = append(args, "COUNT", count)
+	}
+	raw, err := exec.Do(ctx, args...)
+	if err != nil {
+		return nil, 0, Wrap(args, err)
+	}
+	return SplitCursorReply(raw)
+}
+
+// CursorDel releases a cursor that will not be read to the end.
+func CursorDel(ctx context.Context, exec Executor, index string, cursor uint64) error {
+	args := []interface{}{"FT.CURSOR", "DEL", index, cursor}
+	if _, err := exec.Do(ctx, args...); err != nil {
+		return Wrap(args, err)
+	}
+	return nil
+}
+
+// SplitCursorReply splits the [page, cursor id] reply of FT.AGGREGATE …
+// WITHCURSOR and FT.CURSOR READ.
+func SplitCursorReply(raw any) (any, uint64, error) {
+	reply, ok := raw.([]interface{})
+	if !ok || len(reply) != 2 {
+		return nil, 0, errors.New("driver: unexpected cursor reply shape")
+	}
+	id, err := strconv.ParseUint(toString(reply[1]), 10, 64)
+	if err != nil {
+		return nil, 0, errors.New("driver: unexpected cursor id " + toString(reply[1]))
+	}
+	return reply[0], id, nil
+}
@@ -0,0 +1,125 @@
+package driver
+
+import (
+	"context"
+	"log"
+	"sync/atomic"
+	"time"
+)
+
+// Event describes one command for the debug log.  Round-trip events carry
+// Took; the query builders emit a second event with Decode once the reply
+// has been turned into rows.
+type Event struct {
+	Args   []interface{}
+	Took   time.Duration // round trip, zero on decode events
+	Decode time.Duration // reply decoding, zero on round-trip events
+	Err    error
+}
+
+// LogFunc receives debug events.  It must be safe for concurrent use.
+type LogFunc func(ctx context.Context, e Event)
+
+var (
+	debugAll atomic.Bool
+	logFn    atomic.Pointer[LogFunc]
+)
+
+// SetDebug turns debug logging on or off for every executor in the process,
+// like GORM's Debug mode.  Meant for local development and support
+// escalations: the full, unredacted command is logged.
+func SetDebug(on bool) { debugAll.Store(on) }
+
+// SetLogger installs the hook debug events go to.  nil restores the default,
+// which prints through the standard log package.
+func SetLogger(fn LogFunc) {
+	if fn == nil {
+		logFn.Store(nil)
+		return
+	}
+	logFn.Store(&fn)
+}
+
+// Debug wraps exec so that its commands are logged whatever SetDebug says.
+// repository.Debug uses it for per-repository debugging.
+// Pipelines stay pipelines: a Pipeliner is wrapped into one.
+func Debug(exec Executor) Executor {
+	switch exec.(type) {
+	case *debugExec, *debugPipe:
+		return exec
+	}
+	if p, ok := exec.(Pipeliner); ok {
+		return &debugPipe{debugExec{exec}, p}
+	}
+	return &debugExec{exec}
+}
+
+type debugExec struct{ Executor }
+
+// debugPipe is debugExec over a Pipeliner, logging each command of a
+// pipeline with the time the whole round trip took.
+type debugPipe struct {
+	debugExec
+	pipe Pipeliner
+}
+
+func (d *debugPipe) Pipeline(ctx context.Context, cmds [][]interface{}) ([]any, error) {
+	start := time.Now()
+	res, err := d.pipe.Pipeline(ctx, cmds)
+	took := time.Since(start)
+	for i, cmd := range cmds {
+		e := Event{Args: cmd, Took: took, Err: err}
+		if i < len(res) {
+			if rerr, ok := res[i].(error); ok {
+				e.Err = rerr
+			}
+		}
+		emit(ctx, e)
+	}
+	return res, err
+}
+
+func (d *debugExec) Do(ctx context.Context, args ...interface{}) (any, error) {
+	if _, ok := d.Executor.(*RedisearchConn); ok && debugAll.Load() {
+		return d.Executor.Do(ctx, args...) // already logged by the conn
+	}
+	start := time.Now()
+	res, err := d.Executor.Do(ctx, args...)
+	emit(ctx, Event{Args: args, Took: time.Since(start), Err: err})
+	return res, err
+}
+
+// Debugging reports whether commands sent through exec are being logged.
+func Debugging(exec Executor) bool {
+	if debugAll.Load() {
+		return true
+	}
+	switch exec.(type) {
+	case *debugExec, *debugPipe:
+		return true
+	}
+	return false
+}
+
+// LogDecode records how long decoding the reply to args took.  It is a no-op
+// unless Debugging(exec).
+func LogDecode(ctx context.Context, exec Executor, args []interface{}, took time.Duration) {
+	if Debugging(exec) {
+		emit(ctx, Event{Args: args, Decode: took})
+	}
+}
+
+func emit(ctx context.Context, e Event) {
+	if fn := logFn.Load(); fn != nil {
+		(*fn)(ctx, e)
+		return
+	}
+	switch {
+	case e.Err != nil:
+		log.Printf("redisorm: [%s] %s: %v", e.Took, stringifyCmd(e.Args), e.Err)
+	case e.Decode > 0:
+		log.Printf("redisorm: [decode %s] %s", e.Decode, stringifyCmd(e.Args))
+	default:
+		log.Printf("redisorm: [%s] %s", e.Took, stringifyCmd(e.Args))
+	}
+}
@@ -0,0 +1,186 @@
+package driver
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+)
+
+// Sentinel errors shared by every redisorm package.  Server replies are
+// mapped onto them by Classify (RedisearchConn.Do does so automatically), so
+// callers can branch with errors.Is without matching message text:
+//
+//	if errors.Is(err, driver.ErrIndexNotFound) {
+//	    // create it and retry
+//	}
+//
+// The index, query and repository packages re-export the ones relevant to
+// them; the values are identical.
+var (
+	ErrIndexNotFound = errors.New("redisorm: index not found")
+	ErrIndexExists   = errors.New("redisorm: index already exists")
+	ErrTimeout       = errors.New("redisorm: timeout")
+	ErrSyntax        = errors.New("redisorm: query syntax error")
+	ErrCursorExpired = errors.New("redisorm: cursor expired or not found")
+	ErrNotFound      = errors.New("redisorm: not found")
+)
+
+// Error attaches a sentinel Kind to the original error.  Both remain
+// reachable: errors.Is(err, ErrSyntax) and errors.As(err, &redis.Error) work.
+type Error struct {
+	Kind error
+	Err  error
+}
+
+func (e *Error) Error() string   { return e.Err.Error() }
+func (e *Error) Unwrap() []error { return []error{e.Kind, e.Err} }
+
+// Classify maps a raw executor error onto the sentinel taxonomy.  Unknown
+// errors and errors that are already classified are returned unchanged.
+func Classify(err error) error {
+	if err == nil {
+		return nil
+	}
+	var done *Error
+	if errors.As(err, &done) {
+		return err
+	}
+	if kind := kindOf(err); kind != nil {
+		return &Error{Kind: kind, Err: err}
+	}
+	return err
+}
+
+func kindOf(err error) error {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return ErrTimeout
+	}
+	var ne net.Error
+	if errors.As(err, &ne) && ne.Timeout() {
+		return ErrTimeout
+	}
+
+	msg := strings.ToLower(err.Error())
+	switch {
+	case strings.Contains(msg, "unknown index name"),
+		strings.Contains(msg, "no such index"),
+		strings.Contains(msg, "unknown index"):
+		return ErrIndexNotFound
+	case strings.Contains(msg, "index already exists"):
+		return ErrIndexExists
+	case strings.Contains(msg, "cursor not found"):
+		return ErrCursorExpired
+	case strings.Contains(msg, "syntax error"),
+		strings.Contains(msg, "unknown field"),
+		strings.Contains(msg, "unknown argument"):
+		return ErrSyntax
+	case strings.Contains(msg, "timeout limit was reached"):
+		return ErrTimeout
+	}
+	return nil
+}
+
+// CommandError gives an execution error its context: the command verb, the
+// index it targeted and a redacted, truncated copy of the query, so a
+// production log line says what failed without leaking the values being
+// searched for.  Error() prints only that summary plus the error class;
+// Unwrap leads to the classified server error, and Args returns the full
+// command for local debugging.
+type CommandError struct {
+	Verb  string // FT.SEARCH, HSET, …
+	Index string // FT.* commands only
+	Query string // redacted query text (FT.SEARCH / FT.AGGREGATE)
+	Err   error
+	args  []interface{}
+}
+
+func (e *CommandError) Error() string {
+	var sb strings.Builder
+	sb.WriteString("redisorm: ")
+	sb.WriteString(e.Verb)
+	if e.Index != "" {
+		sb.WriteByte(' ')
+		sb.WriteString(e.Index)
+	}
+	if e.Query != "" {
+		sb.WriteString(" \"")
+		sb.WriteString(e.Query)
+		sb.WriteByte('"')
+	}
+	sb.WriteString(": ")
+	var kinded *Error
+	if errors.As(e.Err, &kinded) {
+		sb.WriteString(strings.TrimPrefix(kinded.Kind.Error(), "redisorm: "))
+	} else {
+		sb.WriteString(e.Err.Error())
+	}
+	return sb.String()
+}
+
+func (e *CommandError) Unwrap() error { return e.Err }
+
+// Args returns the unredacted command.  Don't log it in production.
+func (e *CommandError) Args() []interface{} { return e.args }
+
+// maxQueryLen bounds the redacted query kept in a CommandError.
+const maxQueryLen = 120
+
+// Wrap classifies err and attaches the command context.  Executors and
+// packages that call Do directly use it so every error looks the same.
+// Already-wrapped errors are returned unchanged.
+func Wrap(args []interface{}, err error) error {
+	if err == nil {
+		return nil
+	}
+	var ce *CommandError
+	if errors.As(err, &ce) {
+		return err
+	}
+	ce = &CommandError{Err: Classify(err), args: args}
+	if len(args) > 0 {
+		ce.Verb = strings.ToUpper(toString(args[0]))
+	}
+	if strings.HasPrefix(ce.Verb, "FT.") && len(args) > 1 {
+		ce.Index = toString(args[1])
+		if (ce.Verb == "FT.SEARCH" || ce.Verb == "FT.AGGREGATE") && len(args) > 2 {
+			ce.Query = Redact(toString(args[2]))
+		}
+	}
+	return ce
+}
+
+// Redact replaces the literal values of a RediSearch query with "?" while
+// keeping field names and structure, and truncates long queries:
+//
+//	(@email:{bob@example.com} @qty:[1 5])  →  (@email:{?} @qty:[? ?])
+func Redact(query string) string {
+	var sb strings.Builder
+	inValue := false
+	afterField := false
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		switch {
+		case c == '@' && !inValue:
+			afterField = true
+			sb.WriteByte(c)
+		case afterField:
+			sb.WriteByte(c)
+			if c == ':' {
+				afterField = false
+			}
+		case strings.IndexByte("(){}[]|-~ *$", c) >= 0:
+			inValue = false
+			sb.WriteByte(c)
+		default:
+			if !inValue {
+				sb.WriteByte('?')
+				inValue = true
+			}
+		}
+		if sb.Len() >= maxQueryLen {
+			return sb.String() + "…"
+		}
+	}
+	return sb.String()
+}
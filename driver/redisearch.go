@@ -35,6 +35,89 @@ type Executor interface {
 	Do(ctx context.Context, args ...interface{}) (any, error)
 }
 
+// Pipeliner is implemented by executors that can send several commands in a
+// single round trip.  RedisearchConn satisfies it.
+type Pipeliner interface {
+	Pipeline(ctx context.Context, cmds [][]interface{}) ([]any, error)
+}
+
+// ErrNoPipeline is returned for commands that only work when sent on one
+// connection together with others (WAIT after a write, MULTI … EXEC) when
+// the executor is not a Pipeliner: its sequential Do calls may each take a
+// different pooled connection.
+var ErrNoPipeline = errors.New("redisorm: executor does not support pipelining")
+
+// DoBatch sends cmds through exec's pipeline when it has one, falling back to
+// sequential Do calls otherwise.  Per-command failures are returned in place
+// as error values; only transport-level failures abort the batch.
+func DoBatch(ctx context.Context, exec Executor, cmds [][]interface{}) ([]any, error) {
+	if p, ok := exec.(Pipeliner); ok {
+		return p.Pipeline(ctx, cmds)
+	}
+	out := make([]any, len(cmds))
+	for i, cmd := range cmds {
+		res, err := exec.Do(ctx, cmd...)
+		if err != nil {
+			out[i] = Wrap(cmd, err)
+			continue
+		}
+		out[i] = res
+	}
+	return out, nil
+}
+
+// DoTx runs cmds as one MULTI/EXEC transaction through exec's pipeline,
+// followed on the same connection by after: commands such as WAIT that must
+// see the transaction's writes but do not belong inside it.  Replies are
+// returned in place as by DoBatch, one per command of cmds, then of after.
+// Executors that cannot pipeline get ErrNoPipeline, as a transaction spread
+// over pooled connections is none.
+func DoTx(ctx context.Context, exec Executor, cmds [][]interface{}, after ...[]interface{}) ([]any, error) {
+	p, ok := exec.(Pipeliner)
+	if !ok {
+		return nil, ErrNoPipeline
+	}
+	batch := make([][]interface{}, 0, len(cmds)+len(after)+2)
+	batch = append(batch, []interface{}{"MULTI"})
+	batch = append(batch, cmds...)
+	batch = append(batch, []interface{}{"EXEC"})
+	batch = append(batch, after...)
+	res, err := p.Pipeline(ctx, batch)
+	if err != nil {
+		return nil, err
+	}
+	if err, ok := res[0].(error); ok {
+		return nil, err
+	}
+
+	execReply := res[len(cmds)+1]
+	replies, _ := execReply.([]interface{})
+	out := make([]any, 0, len(cmds)+len(after))
+	for i, cmd := range cmds {
+		queued, reply := res[i+1], any(nil)
+		switch {
+		case isErr(queued): // rejected while queueing
+			reply = queued
+		case isErr(execReply): // EXECABORT: nothing ran
+			reply = execReply
+		case i < len(replies):
+			reply = replies[i]
+			if err, ok := reply.(error); ok {
+				reply = Wrap(cmd, err)
+			}
+		default:
+			reply = fmt.Errorf("driver: unexpected EXEC reply %T", execReply)
+		}
+		out = append(out, reply)
+	}
+	return append(out, res[len(cmds)+2:]...), nil
+}
+
+func isErr(v any) bool {
+	_, ok := v.(error)
+	return ok
+}
+
 // RedisearchConn implements redisorm.Executor on top of *redis.Client.
 type RedisearchConn struct {
 	client *redis.Client
@@ -60,7 +143,10 @@ func (rc *RedisearchConn) Do(ctx context.Context, args ...interface{}) (any, err
 	if err != nil {
 		span.RecordError(err)
 	}
-	return res, err
+	if debugAll.Load() {
+		emit(ctx, Event{Args: args, Took: elapsed, Err: err})
+	}
+	return res, Wrap(args, err)
 }
 
 // Close conveniently closes the underlying *redis.Client.
@@ -115,14 +201,17 @@ func (rc *RedisearchConn) Pipeline(
 	for i, cmd := range cmds {
 		results[i] = pipe.Do(ctx, cmd...)
 	}
-	if _, err := pipe.Exec(ctx); err != nil {
-		return nil, err
+	// Exec reports the first failed command; server-side reply errors are
+	// handed back per command below, only transport errors abort the batch.
+	var replyErr redis.Error
+	if _, err := pipe.Exec(ctx); err != nil && !errors.As(err, &replyErr) {
+		return nil, Classify(err)
 	}
 
 	out := make([]any, len(results))
 	for i, r := range results {
 		if err := r.Err(); err != nil {
-			out[i] = err
+			out[i] = Wrap(cmds[i], err)
 		} else {
 			out[i] = r.Val()
 		}
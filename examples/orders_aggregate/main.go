@@ -13,6 +13,7 @@ import (
 	"github.com/manojoshi/redisorm/index"
 	q "github.com/manojoshi/redisorm/query"
 	"github.com/manojoshi/redisorm/repository"
+	"github.com/manojoshi/redisorm/scan"
 )
 
 type Order struct {
@@ -61,12 +62,16 @@ func main() {
 		log.Fatalf("aggregate: %v", err)
 	}
 
-	fmt.Println(results)
+	fmt.Println(scan.Maps(results))
 
 	fmt.Println("Warehouse stats")
 	for _, row := range results {
-		fmt.Printf("WH %s, status %s → orders=%s  total_qty=%s  avg_qty=%s\n",
-			row["warehouse_id"], row["status"], row["orders"], row["total_qty"], row["avg_qty"])
+		fmt.Printf("WH %s, status %s → orders=%d  total_qty=%d  avg_qty=%.2f\n",
+			row.String("warehouse_id"), row.String("status"),
+			row.Int("orders"), row.Int("total_qty"), row.Float("avg_qty"))
+		if err := row.Err(); err != nil {
+			log.Fatal(err)
+		}
 	}
 }
 
@@ -10,11 +10,13 @@ import (
 	"github.com/redis/go-redis/v9"
 
 	"github.com/manojoshi/redisorm/driver"
+	"github.com/manojoshi/redisorm/fixtures"
 	"github.com/manojoshi/redisorm/index"
-	q "github.com/manojoshi/redisorm/query"
 	"github.com/manojoshi/redisorm/repository"
 )
 
+//go:generate go run github.com/manojoshi/redisorm/cmd/redisorm-gen -type Order
+
 type Order struct {
 	ID        string `redisorm:"@order_id,TAG,SORTABLE"`
 	Status    string `redisorm:"@status,TAG"`
@@ -41,15 +43,15 @@ func main() {
 		log.Fatalf("index create: %v", err)
 	}
 
-	seed(ctx, rdb)
+	seed(ctx, conn)
 
 	repo := repository.New("order_idx", conn)
 
 	orders, err := repo.Search(
 		ctx,
-		q.MatchAll(),
+		OrderFields.Status.Eq("PENDING"),
 		repository.Select("order_id", "qty", "promise_ts"),
-		repository.SortAsc("promise_ts"),
+		repository.SortAsc(OrderFields.PromiseTS.Name()),
 		repository.Limit(0, 1),
 	)
 	if err != nil {
@@ -57,33 +59,17 @@ func main() {
 	}
 
 	for _, a := range orders {
-		fmt.Printf("a %+v %+v\n", a, len(orders))
+		fmt.Printf("a %+v %+v\n", a.Fields, len(orders))
 	}
 }
 
 // seed writes two demo orders into Redis hashes.
-func seed(ctx context.Context, rdb *redis.Client) {
-	hset := func(key string, m map[string]any) {
-		if err := rdb.HSet(ctx, key, m).Err(); err != nil {
-			log.Fatal(err)
-		}
-	}
+func seed(ctx context.Context, conn driver.Executor) {
 	now := time.Now().Unix()
-
-	hset("order:101", map[string]any{
-		"order_id":     "101",
-		"status":       "PENDING",
-		"warehouse_id": 1,
-		"qty":          2,
-		"promise_ts":   now + 3600,
-		"created_ts":   now,
-	})
-	hset("order:102", map[string]any{
-		"order_id":     "102",
-		"status":       "PENDING",
-		"warehouse_id": 2,
-		"qty":          1,
-		"promise_ts":   now + 7200,
-		"created_ts":   now,
-	})
+	if _, err := fixtures.Load(ctx, conn, fixtures.Of("order:{order_id}",
+		Order{ID: "101", Status: "PENDING", Warehouse: 1, Qty: 2, PromiseTS: now + 3600, CreatedTS: now},
+		Order{ID: "102", Status: "PENDING", Warehouse: 2, Qty: 1, PromiseTS: now + 7200, CreatedTS: now},
+	)); err != nil {
+		log.Fatal(err)
+	}
 }
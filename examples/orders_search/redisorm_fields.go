@@ -0,0 +1,22 @@
+// Code generated by redisorm-gen; DO NOT EDIT.
+
+package main
+
+import q "github.com/manojoshi/redisorm/query"
+
+// OrderFields are the typed query fields of Order.
+var OrderFields = struct {
+	ID        q.TagField
+	Status    q.TagField
+	Warehouse q.TagField
+	Qty       q.NumericField
+	PromiseTS q.NumericField
+	CreatedTS q.NumericField
+}{
+	ID:        "order_id",
+	Status:    "status",
+	Warehouse: "warehouse_id",
+	Qty:       "qty",
+	PromiseTS: "promise_ts",
+	CreatedTS: "created_ts",
+}
@@ -0,0 +1,178 @@
+// Package export streams result sets to JSON, NDJSON or CSV for API handlers
+// and export endpoints.
+//
+//	fields := []string{"order_id", "status", "qty"}
+//	docs, _ := repo.Search(ctx, where, repository.Select(fields...))
+//	w.Header().Set("Content-Type", "text/csv")
+//	err := export.Docs(w, export.CSV, docs, fields...)
+//
+// Columns follow the fields given – pass the same list as Select.  Without
+// one, struct rows use tag order and map rows the sorted union of their keys.
+package export
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/manojoshi/redisorm/index"
+	"github.com/manojoshi/redisorm/repository"
+	"github.com/manojoshi/redisorm/scan"
+)
+
+// Format selects the output encoding.
+type Format int
+
+const (
+	JSON   Format = iota // one JSON array
+	NDJSON               // one JSON object per line
+	CSV                  // header row, then one row per record
+)
+
+// Maps writes map rows, e.g. from Repo.Aggregate.
+func Maps(w io.Writer, f Format, rows []map[string]string, fields ...string) error {
+	if len(fields) == 0 {
+		fields = unionKeys(rows)
+	}
+	return write(w, f, fields, len(rows), func(i int) map[string]any {
+		out := make(map[string]any, len(rows[i]))
+		for k, v := range rows[i] {
+			out[k] = v
+		}
+		return out
+	})
+}
+
+// Docs writes the result of Repository.Search / Aggregate.
+func Docs(w io.Writer, f Format, docs []*scan.Doc, fields ...string) error {
+	return Maps(w, f, scan.Maps(docs), fields...)
+}
+
+// Structs writes tagged structs; keys are the redisorm field names and
+// values are encoded as LoadHash would store them.
+func Structs[T any](w io.Writer, f Format, rows []T, fields ...string) error {
+	if len(fields) == 0 {
+		fields = tagOrder(reflect.TypeOf((*T)(nil)).Elem())
+	}
+	encoded := make([]map[string]any, len(rows))
+	for i := range rows {
+		m, err := repository.Encode(rows[i])
+		if err != nil {
+			return err
+		}
+		encoded[i] = m
+	}
+	return write(w, f, fields, len(rows), func(i int) map[string]any { return encoded[i] })
+}
+
+func write(w io.Writer, f Format, fields []string, n int, row func(int) map[string]any) error {
+	switch f {
+	case CSV:
+		cw := csv.NewWriter(w)
+		if err := cw.Write(fields); err != nil {
+			return err
+		}
+		rec := make([]string, len(fields))
+		for i := 0; i < n; i++ {
+			m := row(i)
+			for j, k := range fields {
+				if v, ok := m[k]; ok {
+					rec[j] = fmt.Sprint(v)
+				} else {
+					rec[j] = ""
+				}
+			}
+			if err := cw.Write(rec); err != nil {
+				return err
+			}
+		}
+		cw.Flush()
+		return cw.Error()
+
+	case JSON, NDJSON:
+		bw := bufio.NewWriter(w)
+		if f == JSON {
+			bw.WriteByte('[')
+		}
+		for i := 0; i < n; i++ {
+			if i > 0 && f == JSON {
+				bw.WriteByte(',')
+			}
+			if err := writeObject(bw, fields, row(i)); err != nil {
+				return err
+			}
+			if f == NDJSON {
+				bw.WriteByte('\n')
+			}
+		}
+		if f == JSON {
+			bw.WriteByte(']')
+		}
+		return bw.Flush()
+	}
+	return fmt.Errorf("export: unknown format %d", f)
+}
+
+// writeObject emits one JSON object with keys in fields order (encoding/json
+// would sort them); fields missing from m are omitted.
+func writeObject(w *bufio.Writer, fields []string, m map[string]any) error {
+	w.WriteByte('{')
+	first := true
+	for _, k := range fields {
+		v, ok := m[k]
+		if !ok {
+			continue
+		}
+		if !first {
+			w.WriteByte(',')
+		}
+		first = false
+		kb, _ := json.Marshal(k)
+		vb, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Errorf("export: field %s: %w", k, err)
+		}
+		w.Write(kb)
+		w.WriteByte(':')
+		w.Write(vb)
+	}
+	w.WriteByte('}')
+	return nil
+}
+
+func unionKeys(rows []map[string]string) []string {
+	seen := map[string]bool{}
+	var out []string
+	for _, r := range rows {
+		for k := range r {
+			if !seen[k] {
+				seen[k] = true
+				out = append(out, k)
+			}
+		}
+	}
+	slices.Sort(out)
+	return out
+}
+
+// tagOrder lists the stored fields of rt in tag order – embedded and
+// nested fragments flattened, each SHADOW field followed by its numeric
+// sibling – matching the keys repository.Encode writes.
+func tagOrder(rt reflect.Type) []string {
+	var out []string
+	for _, f := range scan.Fields(rt) {
+		if scan.MetaField(f.Name) {
+			continue
+		}
+		out = append(out, f.Name)
+		if slices.ContainsFunc(f.Attrs, func(a string) bool { return strings.EqualFold(a, "SHADOW") }) {
+			out = append(out, f.Name+index.ShadowSuffix)
+		}
+	}
+	return out
+}
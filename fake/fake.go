@@ -0,0 +1,223 @@
+// Package fake generates random documents for redisorm models, for load
+// tests and demo environments.  Values are derived from the redisorm tags:
+// field types, the optional validation attributes ENUM=a|b|c, MIN=n and
+// MAX=n, and a few naming conventions (…_id, …_ts, email, name, city).
+//
+//	f := fake.New(42)
+//	orders := fake.Records[Order](f, 10_000)
+//	rep, err := repo.LoadBulk(ctx, "order_idx", "order:", orders,
+//	    func(o any) string { return o.(Order).ID })
+package fake
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/manojoshi/redisorm/scan"
+)
+
+// Faker is a seeded generator; equal seeds give equal documents.  It is not
+// safe for concurrent use.
+type Faker struct {
+	rnd *rand.Rand
+	seq int
+	now time.Time
+}
+
+// New returns a Faker seeded with seed.
+func New(seed int64) *Faker {
+	return &Faker{rnd: rand.New(rand.NewSource(seed)), now: time.Now()}
+}
+
+// Generate returns n random T.
+func Generate[T any](f *Faker, n int) []T {
+	out := make([]T, n)
+	for i := range out {
+		f.fill(reflect.ValueOf(&out[i]).Elem())
+	}
+	return out
+}
+
+// Records is Generate boxed for Repo.LoadBulk.
+func Records[T any](f *Faker, n int) []any {
+	docs := Generate[T](f, n)
+	out := make([]any, n)
+	for i := range docs {
+		out[i] = docs[i]
+	}
+	return out
+}
+
+// Fill populates the tagged fields of the struct ptr points to.
+func (f *Faker) Fill(ptr any) error {
+	rv := reflect.ValueOf(ptr)
+	if rv.Kind() != reflect.Pointer || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("fake: Fill needs a pointer to struct, got %T", ptr)
+	}
+	f.fill(rv.Elem())
+	return nil
+}
+
+// rule is what a tag says about one field.
+type rule struct {
+	name     string
+	typ      string // TEXT, TAG, NUMERIC, GEO, GEOSHAPE, VECTOR
+	enum     []string
+	min, max float64
+	hasMin   bool
+	hasMax   bool
+}
+
+func parseRule(tag string) rule {
+	parts := strings.Split(tag, ",")
+	r := rule{name: strings.TrimPrefix(parts[0], "@"), typ: "TEXT"}
+	for _, a := range parts[1:] {
+		k, v, _ := strings.Cut(a, "=")
+		switch strings.ToUpper(k) {
+		case "NUMERIC", "TAG", "GEO", "GEOSHAPE", "VECTOR":
+			r.typ = strings.ToUpper(k)
+		case "ENUM":
+			r.enum = strings.Split(v, "|")
+		case "MIN":
+			r.min, _ = strconv.ParseFloat(v, 64)
+			r.hasMin = true
+		case "MAX":
+			r.max, _ = strconv.ParseFloat(v, 64)
+			r.hasMax = true
+		}
+	}
+	return r
+}
+
+func (f *Faker) fill(v reflect.Value) {
+	f.seq++
+	for _, sf := range scan.Fields(v.Type()) {
+		fv := v.FieldByIndex(sf.Index)
+		if !fv.CanSet() || scan.MetaField(sf.Name) {
+			continue
+		}
+		f.value(fv, parseRule(sf.Tag))
+	}
+}
+
+func (f *Faker) value(fv reflect.Value, r rule) {
+	if fv.Type() == reflect.TypeOf([]string(nil)) { // multi-value TAG
+		pool := r.enum
+		if len(pool) == 0 {
+			pool = tagPool
+		}
+		vs := make([]string, 1+f.rnd.Intn(3))
+		for i := range vs {
+			vs[i] = pool[f.rnd.Intn(len(pool))]
+		}
+		fv.Set(reflect.ValueOf(vs))
+		return
+	}
+	if len(r.enum) > 0 {
+		pick := r.enum[f.rnd.Intn(len(r.enum))]
+		setString(fv, pick)
+		return
+	}
+	lname := strings.ToLower(r.name)
+	switch fv.Kind() {
+	case reflect.String:
+		fv.SetString(f.text(lname, r))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		fv.SetInt(int64(f.number(lname, r)))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		fv.SetUint(uint64(max(f.number(lname, r), 0)))
+	case reflect.Float32, reflect.Float64:
+		fv.SetFloat(f.number(lname, r))
+	case reflect.Bool:
+		fv.SetBool(f.rnd.Intn(2) == 1)
+	case reflect.Struct:
+		if fv.Type() == reflect.TypeOf(time.Time{}) {
+			fv.Set(reflect.ValueOf(f.instant()))
+		}
+	}
+}
+
+func (f *Faker) number(name string, r rule) float64 {
+	lo, hi := 0.0, 1000.0
+	switch {
+	case strings.HasSuffix(name, "_ts") || strings.HasSuffix(name, "_at"):
+		return float64(f.instant().Unix())
+	case strings.HasSuffix(name, "_id"):
+		lo, hi = 1, 100
+	}
+	if r.hasMin {
+		lo = r.min
+	}
+	if r.hasMax {
+		hi = r.max
+	}
+	if hi <= lo {
+		return lo
+	}
+	return lo + f.rnd.Float64()*(hi-lo)
+}
+
+// instant is within a week either side of the Faker's creation.
+func (f *Faker) instant() time.Time {
+	week := int64(7 * 24 * time.Hour)
+	return f.now.Add(time.Duration(f.rnd.Int63n(2*week) - week)).Truncate(time.Second)
+}
+
+var (
+	firstNames = []string{"Asha", "Ben", "Chen", "Dana", "Emeka", "Fatima", "Goran", "Hana", "Ivan", "Júlia"}
+	lastNames  = []string{"Iyer", "Smith", "Wang", "Cohen", "Okafor", "Haddad", "Novak", "Sato", "Petrov", "Costa"}
+	cities     = []string{"Pune", "Leeds", "Austin", "Lagos", "Osaka", "Lyon", "Recife", "Gdańsk", "Perth", "Tartu"}
+	words      = []string{"swift", "amber", "solid", "quiet", "north", "prime", "lunar", "brisk", "cobalt", "maple"}
+	tagPool    = []string{"alpha", "beta", "gamma", "delta", "epsilon"}
+)
+
+func (f *Faker) text(name string, r rule) string {
+	pick := func(xs []string) string { return xs[f.rnd.Intn(len(xs))] }
+	switch {
+	case name == "id" || strings.HasSuffix(name, "_id"):
+		if r.typ == "NUMERIC" {
+			return strconv.Itoa(int(f.number(name, r)))
+		}
+		return fmt.Sprintf("%06d", f.seq)
+	case strings.Contains(name, "email"):
+		return strings.ToLower(pick(firstNames)+"."+pick(lastNames)) + "@example.com"
+	case strings.Contains(name, "name"):
+		return pick(firstNames) + " " + pick(lastNames)
+	case strings.Contains(name, "city"):
+		return pick(cities)
+	}
+	switch r.typ {
+	case "TAG":
+		return pick(tagPool)
+	case "NUMERIC":
+		return strconv.FormatFloat(f.number(name, r), 'f', 2, 64)
+	case "GEO":
+		return fmt.Sprintf("%.5f,%.5f", f.rnd.Float64()*360-180, f.rnd.Float64()*170-85)
+	case "GEOSHAPE":
+		return fmt.Sprintf("POINT(%.5f %.5f)", f.rnd.Float64()*360-180, f.rnd.Float64()*170-85)
+	}
+	n := 2 + f.rnd.Intn(4)
+	ws := make([]string, n)
+	for i := range ws {
+		ws[i] = pick(words)
+	}
+	return strings.Join(ws, " ")
+}
+
+// setString assigns an ENUM choice, parsing it for numeric fields.
+func setString(fv reflect.Value, s string) {
+	switch fv.Kind() {
+	case reflect.String:
+		fv.SetString(s)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, _ := strconv.ParseInt(s, 10, 64)
+		fv.SetInt(n)
+	case reflect.Float32, reflect.Float64:
+		n, _ := strconv.ParseFloat(s, 64)
+		fv.SetFloat(n)
+	}
+}
@@ -0,0 +1,209 @@
+// Package fixtures seeds test data declared as Go values (or JSON / YAML
+// files) mapped to redisorm models, loads it with pipelining and removes
+// exactly the keys it wrote afterwards.
+//
+//	orders := fixtures.Of("order:{order_id}",
+//	    Order{ID: "101", Status: "PENDING", Qty: 2},
+//	    Order{ID: "102", Status: "SHIPPED", Qty: 1},
+//	)
+//	loaded, err := fixtures.Load(ctx, conn, orders)
+//	…
+//	defer loaded.Cleanup(ctx)
+//
+// Tests use fixturestest.Seed, which ties the cleanup to the test; this
+// package does not import testing, so seeding tools and examples can use it.
+package fixtures
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/manojoshi/redisorm/driver"
+	"github.com/manojoshi/redisorm/repository"
+)
+
+// batchSize bounds how many commands go in one pipeline.
+const batchSize = 500
+
+// Fixture is one document to write.
+type Fixture struct {
+	Key    string
+	Record any // tagged struct or map[string]any
+}
+
+// Set is a group of fixtures, usually one model.  A key-template error is
+// kept and reported by Load.
+type Set struct {
+	items []Fixture
+	err   error
+}
+
+// Of keys records with a repository.KeyTemplate pattern.
+func Of(keyTemplate string, records ...any) Set {
+	kf, err := repository.KeyTemplate(keyTemplate)
+	if err != nil {
+		return Set{err: err}
+	}
+	return With(kf, records...)
+}
+
+// With keys records with kf.
+func With(kf repository.KeyFunc, records ...any) Set {
+	s := Set{items: make([]Fixture, 0, len(records))}
+	for _, r := range records {
+		key, err := kf(r)
+		if err != nil {
+			return Set{err: fmt.Errorf("fixtures: %w", err)}
+		}
+		s.items = append(s.items, Fixture{Key: key, Record: r})
+	}
+	return s
+}
+
+// Keyed wraps explicit fixtures.
+func Keyed(items ...Fixture) Set { return Set{items: items} }
+
+// FromJSON reads a JSON array of T and keys it with keyTemplate.  T is the
+// model; fields are matched by its json tags, or Go field names.
+func FromJSON[T any](r io.Reader, keyTemplate string) Set {
+	var recs []T
+	if err := json.NewDecoder(r).Decode(&recs); err != nil {
+		return Set{err: fmt.Errorf("fixtures: %w", err)}
+	}
+	anys := make([]any, len(recs))
+	for i := range recs {
+		anys[i] = recs[i]
+	}
+	return Of(keyTemplate, anys...)
+}
+
+// FromYAML reads a YAML sequence of T and keys it with keyTemplate.  The
+// documents are matched to T's fields exactly as FromJSON does:
+//
+//	# testdata/orders.yaml
+//	- id: "101"
+//	  status: PENDING
+//	  qty: 2
+func FromYAML[T any](r io.Reader, keyTemplate string) Set {
+	var docs []any
+	if err := yaml.NewDecoder(r).Decode(&docs); err != nil && err != io.EOF {
+		return Set{err: fmt.Errorf("fixtures: %w", err)}
+	}
+	body, err := json.Marshal(docs)
+	if err != nil {
+		return Set{err: fmt.Errorf("fixtures: %w", err)}
+	}
+	return FromJSON[T](strings.NewReader(string(body)), keyTemplate)
+}
+
+// File is FromYAML on a .yaml / .yml file and FromJSON on any other, e.g.
+// testdata/orders.json.
+func File[T any](path, keyTemplate string) Set {
+	f, err := os.Open(path)
+	if err != nil {
+		return Set{err: fmt.Errorf("fixtures: %w", err)}
+	}
+	defer f.Close()
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return FromYAML[T](f, keyTemplate)
+	}
+	return FromJSON[T](f, keyTemplate)
+}
+
+// Loaded tracks the keys written by Load.
+type Loaded struct {
+	exec driver.Executor
+	mu   sync.Mutex
+	keys []string
+}
+
+// Keys returns the keys written so far.
+func (l *Loaded) Keys() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]string(nil), l.keys...)
+}
+
+// Load writes every set with pipelined HSETs.  On error the keys already
+// written are still tracked, so Cleanup removes them.
+func Load(ctx context.Context, exec driver.Executor, sets ...Set) (*Loaded, error) {
+	l := &Loaded{exec: exec}
+	var (
+		cmds [][]interface{}
+		keys []string
+	)
+	flush := func() error {
+		if len(cmds) == 0 {
+			return nil
+		}
+		res, err := driver.DoBatch(ctx, exec, cmds)
+		if err != nil {
+			return err
+		}
+		var errs []error
+		l.mu.Lock()
+		for i, r := range res {
+			if e, ok := r.(error); ok {
+				errs = append(errs, e)
+				continue
+			}
+			l.keys = append(l.keys, keys[i])
+		}
+		l.mu.Unlock()
+		cmds, keys = cmds[:0], keys[:0]
+		return errors.Join(errs...)
+	}
+
+	for _, s := range sets {
+		if s.err != nil {
+			return l, s.err
+		}
+		for _, f := range s.items {
+			vals, err := repository.Encode(f.Record)
+			if err != nil {
+				return l, fmt.Errorf("fixtures: %s: %w", f.Key, err)
+			}
+			cmd := []interface{}{"HSET", f.Key}
+			for k, v := range vals {
+				cmd = append(cmd, k, v)
+			}
+			cmds, keys = append(cmds, cmd), append(keys, f.Key)
+			if len(cmds) == batchSize {
+				if err := flush(); err != nil {
+					return l, err
+				}
+			}
+		}
+	}
+	return l, flush()
+}
+
+// Cleanup deletes every key Load wrote.
+func (l *Loaded) Cleanup(ctx context.Context) error {
+	keys := l.Keys()
+	for len(keys) > 0 {
+		n := min(len(keys), batchSize)
+		args := []interface{}{"DEL"}
+		for _, k := range keys[:n] {
+			args = append(args, k)
+		}
+		if _, err := l.exec.Do(ctx, args...); err != nil {
+			return driver.Wrap(args, err)
+		}
+		keys = keys[n:]
+	}
+	l.mu.Lock()
+	l.keys = nil
+	l.mu.Unlock()
+	return nil
+}
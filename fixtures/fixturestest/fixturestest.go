@@ -0,0 +1,34 @@
+// Package fixturestest ties package fixtures to Go tests:
+//
+//	func TestSearch(t *testing.T) {
+//	    fixturestest.Seed(t, conn, fixtures.Of("order:{order_id}",
+//	        Order{ID: "101", Status: "PENDING", Qty: 2},
+//	        Order{ID: "102", Status: "SHIPPED", Qty: 1},
+//	    )) // cleaned up when the test ends
+//	    …
+//	}
+package fixturestest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/manojoshi/redisorm/driver"
+	"github.com/manojoshi/redisorm/fixtures"
+)
+
+// Seed loads sets for a test and registers their cleanup with t.Cleanup.
+// Failures are fatal to the test.
+func Seed(t testing.TB, exec driver.Executor, sets ...fixtures.Set) *fixtures.Loaded {
+	t.Helper()
+	l, err := fixtures.Load(context.Background(), exec, sets...)
+	t.Cleanup(func() {
+		if err := l.Cleanup(context.Background()); err != nil {
+			t.Errorf("fixtures: cleanup: %v", err)
+		}
+	})
+	if err != nil {
+		t.Fatalf("fixtures: %v", err)
+	}
+	return l
+}
@@ -6,6 +6,7 @@ require (
 	github.com/redis/go-redis/v9 v9.11.0
 	go.opentelemetry.io/otel v1.37.0
 	golang.org/x/exp v0.0.0-20250620022241-b7579e27df2b
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
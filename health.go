@@ -0,0 +1,145 @@
+// Package redisorm holds cross-cutting helpers that span the driver, index
+// and repository packages.  Most applications import those packages directly;
+// this one provides the operational entry points (health reporting, …).
+package redisorm
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/manojoshi/redisorm/driver"
+	"github.com/manojoshi/redisorm/index"
+)
+
+// HealthReport is what readiness probes and admin endpoints expose.
+type HealthReport struct {
+	OK         bool              // reachable and every index readable without failures
+	Latency    time.Duration     // PING round trip
+	Modules    map[string]string // module name → version ("search" → "20811")
+	UsedMemory int64             // server used_memory in bytes
+	Indexes    []IndexHealth
+}
+
+// IndexHealth describes one index.
+type IndexHealth struct {
+	Name           string
+	Docs           int64
+	Indexing       bool    // initial scan / reindex still running
+	PercentIndexed float64 // 0..1
+	Failures       int64   // documents that failed to index
+	LastError      string  // most recent indexing error, if any
+	MemoryMB       float64 // inverted index + doc table + sortables + vectors
+	Err            error   // FT.INFO failed (unknown index, …)
+}
+
+// Health gathers connectivity, module versions, memory usage and per-index
+// statistics in one call.  Only an unreachable server is reported as an
+// error; everything else ends up in the report (and clears OK).
+//
+//	http.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+//	    rep, err := redisorm.Health(r.Context(), conn, "order_idx")
+//	    if err != nil || !rep.OK {
+//	        w.WriteHeader(http.StatusServiceUnavailable)
+//	    }
+//	    json.NewEncoder(w).Encode(rep)
+//	})
+func Health(ctx context.Context, conn driver.Executor, indexes ...string) (*HealthReport, error) {
+	rep := &HealthReport{Modules: map[string]string{}}
+
+	start := time.Now()
+	if _, err := conn.Do(ctx, "PING"); err != nil {
+		return rep, fmt.Errorf("redisorm: ping: %w", err)
+	}
+	rep.Latency = time.Since(start)
+	rep.OK = true
+
+	if raw, err := conn.Do(ctx, "MODULE", "LIST"); err == nil {
+		for _, m := range asList(raw) {
+			mm := asMap(m)
+			rep.Modules[asString(mm["name"])] = asString(mm["ver"])
+		}
+	}
+	if raw, err := conn.Do(ctx, "INFO", "memory"); err == nil {
+		rep.UsedMemory, _ = strconv.ParseInt(infoField(asString(raw), "used_memory"), 10, 64)
+	}
+
+	for _, name := range indexes {
+		ih := indexHealth(ctx, conn, name)
+		if ih.Err != nil || ih.Failures > 0 {
+			rep.OK = false
+		}
+		rep.Indexes = append(rep.Indexes, ih)
+	}
+	return rep, nil
+}
+
+func indexHealth(ctx context.Context, conn driver.Executor, name string) IndexHealth {
+	info, err := index.Info(ctx, conn, name)
+	if err != nil {
+		return IndexHealth{Name: name, Err: err}
+	}
+	return IndexHealth{
+		Name:           name,
+		Docs:           info.NumDocs,
+		Indexing:       info.Indexing,
+		PercentIndexed: info.PercentIndexed,
+		Failures:       info.Failures,
+		LastError:      info.LastError,
+		MemoryMB:       info.MemoryMB,
+	}
+}
+
+// infoField extracts "key:value" from an INFO section.
+func infoField(info, key string) string {
+	for _, line := range strings.Split(info, "\n") {
+		if v, ok := strings.CutPrefix(strings.TrimSpace(line), key+":"); ok {
+			return v
+		}
+	}
+	return ""
+}
+
+// ---------------------------------------------------------------------
+// reply helpers (RESP-2 flat lists and RESP-3 maps)
+// ---------------------------------------------------------------------
+
+func asMap(v any) map[string]any {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		return t
+	case map[interface{}]interface{}:
+		m := make(map[string]any, len(t))
+		for k, val := range t {
+			m[asString(k)] = val
+		}
+		return m
+	case []interface{}:
+		m := make(map[string]any, len(t)/2)
+		for i := 0; i+1 < len(t); i += 2 {
+			m[asString(t[i])] = t[i+1]
+		}
+		return m
+	}
+	return nil
+}
+
+func asList(v any) []any {
+	l, _ := v.([]interface{})
+	return l
+}
+
+func asString(v any) string {
+	switch t := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return t
+	case []byte:
+		return string(t)
+	default:
+		return fmt.Sprint(t)
+	}
+}
@@ -0,0 +1,99 @@
+package index
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/manojoshi/redisorm/driver"
+)
+
+// CreateAlias points alias at index (FT.ALIASADD); it fails if the alias
+// already exists.
+func CreateAlias(ctx context.Context, exec driver.Executor, alias, index string) error {
+	return aliasCmd(ctx, exec, "FT.ALIASADD", alias, index)
+}
+
+// SwapAlias atomically repoints alias at index, creating it if needed
+// (FT.ALIASUPDATE).  Queries against the alias never see a gap.
+func SwapAlias(ctx context.Context, exec driver.Executor, alias, index string) error {
+	return aliasCmd(ctx, exec, "FT.ALIASUPDATE", alias, index)
+}
+
+// DeleteAlias removes alias; the index it pointed at is untouched.
+func DeleteAlias(ctx context.Context, exec driver.Executor, alias string) error {
+	args := []interface{}{"FT.ALIASDEL", alias}
+	if _, err := exec.Do(ctx, args...); err != nil {
+		return driver.Wrap(args, err)
+	}
+	return nil
+}
+
+func aliasCmd(ctx context.Context, exec driver.Executor, verb, alias, index string) error {
+	args := []interface{}{verb, alias, index}
+	if _, err := exec.Do(ctx, args...); err != nil {
+		return driver.Wrap(args, err)
+	}
+	return nil
+}
+
+// reindexPoll is how often Reindex checks indexing progress.
+const reindexPoll = 500 * time.Millisecond
+
+// Reindex is the zero-downtime path for breaking schema changes: it
+// creates a new index for model named "<alias>_<UTC timestamp>" down to the
+// nanosecond, failing with ErrIndexExists should that name be taken; it waits
+// until the new index has scanned the existing documents, then swaps
+// alias over to it.  Applications query the alias, never the concrete
+// index.  previous is the index the alias pointed at before ("" when the
+// alias is new); drop it once nothing else needs it.
+//
+//	next, prev, err := index.Reindex(ctx, conn, Order{}, "orders",
+//	    index.WithPrefixes("order:"))
+//	if err == nil && prev != "" {
+//	    conn.Do(ctx, "FT.DROPINDEX", prev)
+//	}
+//
+// opts are as for AutoCreate; WithName is overridden and AdditiveOnly has no
+// effect.
+func Reindex(
+	ctx context.Context,
+	exec driver.Executor,
+	model any,
+	alias string,
+	opts ...CreateOpt,
+) (created, previous string, err error) {
+
+	if info, err := Info(ctx, exec, alias); err == nil {
+		previous = info.Name
+	}
+	now := time.Now().UTC()
+	created = fmt.Sprintf("%s_%s%09d", alias, now.Format("20060102T150405"), now.Nanosecond())
+	cfg := &createCfg{}
+	for _, o := range opts {
+		o(cfg)
+	}
+	cfg.name = created
+	// A concurrent Reindex may have taken the name: never adopt its index.
+	if err := create(ctx, exec, cfg, model); err != nil {
+		return "", previous, err
+	}
+
+	tick := time.NewTicker(reindexPoll)
+	defer tick.Stop()
+	for {
+		info, err := Info(ctx, exec, created)
+		if err != nil {
+			return created, previous, err
+		}
+		if !info.Indexing {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return created, previous, fmt.Errorf("index: reindex %s: %w", created, ctx.Err())
+		case <-tick.C:
+		}
+	}
+	return created, previous, SwapAlias(ctx, exec, alias, created)
+}
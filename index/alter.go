@@ -0,0 +1,141 @@
+package index
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/manojoshi/redisorm/driver"
+)
+
+// SchemaDiff compares a live index with a model.
+type SchemaDiff struct {
+	Added   []string      // in the model, not in the index
+	Removed []string      // in the index, not in the model
+	Changed []FieldChange // same name, different type
+}
+
+// FieldChange is a field whose type differs between index and model.
+type FieldChange struct {
+	Field string
+	Index string // type in the live index
+	Model string // type the model asks for
+}
+
+// Compatible reports whether the model only adds fields.
+func (d *SchemaDiff) Compatible() bool { return len(d.Removed) == 0 && len(d.Changed) == 0 }
+
+func (d *SchemaDiff) String() string {
+	var parts []string
+	for _, f := range d.Added {
+		parts = append(parts, "+"+f)
+	}
+	for _, f := range d.Removed {
+		parts = append(parts, "-"+f)
+	}
+	for _, c := range d.Changed {
+		parts = append(parts, fmt.Sprintf("~%s (%s → %s)", c.Field, c.Index, c.Model))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// SchemaConflictError is returned in additive-only mode when the model
+// removes fields or changes their type.
+type SchemaConflictError struct {
+	Index string
+	Diff  *SchemaDiff
+}
+
+func (e *SchemaConflictError) Error() string {
+	return fmt.Sprintf("index: %s: schema change is not additive: %s", e.Index, e.Diff)
+}
+
+// Diff compares the live definition of indexName with model (plus any
+// WithModels) — field names and types; other attributes are not compared.
+func Diff(ctx context.Context, exec driver.Executor, indexName string, model any, opts ...CreateOpt) (*SchemaDiff, error) {
+	cfg := &createCfg{name: indexName}
+	for _, o := range opts {
+		o(cfg)
+	}
+	cfg.name = indexName
+	d, _, err := diff(ctx, exec, cfg, model)
+	return d, err
+}
+
+// Alter brings an existing index in line with model by adding the fields it
+// lacks (FT.ALTER … SCHEMA ADD).  RediSearch cannot drop or retype fields, so
+// those differences are only reported in the returned diff — or, with
+// AdditiveOnly, refused before anything is changed.  Added fields are
+// built as AutoCreate builds them, JSONPaths included under OnJSON.
+func Alter(ctx context.Context, exec driver.Executor, model any, opts ...CreateOpt) (*SchemaDiff, error) {
+	cfg := &createCfg{name: inferIndexName(model)}
+	for _, o := range opts {
+		o(cfg)
+	}
+	return alter(ctx, exec, cfg, model)
+}
+
+func alter(ctx context.Context, exec driver.Executor, cfg *createCfg, model any) (*SchemaDiff, error) {
+	d, wanted, err := diff(ctx, exec, cfg, model)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.additiveOnly && !d.Compatible() {
+		return d, &SchemaConflictError{Index: cfg.name, Diff: d}
+	}
+	if len(d.Added) == 0 {
+		return d, nil
+	}
+
+	var add []fieldSpec
+	for _, f := range wanted {
+		for _, name := range d.Added {
+			if f.name == name {
+				add = append(add, f)
+			}
+		}
+	}
+	args := append([]interface{}{"FT.ALTER", cfg.name, "SCHEMA", "ADD"}, flatten(add)...)
+	if _, err := exec.Do(ctx, args...); err != nil {
+		return d, driver.Wrap(args, err)
+	}
+	return d, nil
+}
+
+// diff returns the differences plus the model's field specs.
+func diff(ctx context.Context, exec driver.Executor, cfg *createCfg, model any) (*SchemaDiff, []fieldSpec, error) {
+	wanted, err := wantedFields(cfg, model)
+	if err != nil {
+		return nil, nil, err
+	}
+	def, err := readDefinition(ctx, exec, cfg.name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	live := make(map[string]string, len(def.fields))
+	for _, f := range def.fields {
+		live[f.name] = f.typ
+	}
+	d := &SchemaDiff{}
+	seen := make(map[string]bool, len(wanted))
+	for _, f := range wanted {
+		if seen[f.name] {
+			continue
+		}
+		seen[f.name] = true
+		typ, ok := live[f.name]
+		switch {
+		case !ok:
+			d.Added = append(d.Added, f.name)
+		case typ != f.typ:
+			d.Changed = append(d.Changed, FieldChange{Field: f.name, Index: typ, Model: f.typ})
+		}
+	}
+	for _, f := range def.fields {
+		if !seen[f.name] {
+			d.Removed = append(d.Removed, f.name)
+		}
+	}
+	return d, wanted, nil
+}
@@ -0,0 +1,48 @@
+package index
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/manojoshi/redisorm/driver"
+)
+
+// Clone creates index dst with the same definition as src — schema, key
+// type, prefixes, filter, language, score and stopwords — as read back from
+// FT.INFO.  overrides are applied on top, typically a new prefix:
+//
+//	err := index.Clone(ctx, conn, "order_idx", "order_idx_v2",
+//	    index.WithPrefixes("order:v2:"))
+//
+// WithName and WithModels are ignored; dst names the new index.  Unlike
+// AutoCreate, an existing dst is an error: a clone is the first step of a
+// reindex and silently reusing a stale index would defeat it.
+func Clone(ctx context.Context, exec driver.Executor, src, dst string, overrides ...CreateOpt) error {
+	def, err := readDefinition(ctx, exec, src)
+	if err != nil {
+		return err
+	}
+
+	cfg := &createCfg{
+		prefixes:      def.prefixes,
+		onJson:        def.onJSON,
+		filter:        def.filter,
+		language:      def.language,
+		languageField: def.langField,
+		score:         def.score,
+		scoreField:    def.scoreField,
+	}
+	if def.hasStop {
+		cfg.stopwords = append([]string{}, def.stopwords...)
+	}
+	for _, o := range overrides {
+		o(cfg)
+	}
+	cfg.name, cfg.models = dst, nil
+
+	args := createArgs(cfg, flatten(def.fields))
+	if _, err := exec.Do(ctx, args...); err != nil {
+		return fmt.Errorf("index: clone %s → %s: %w", src, dst, driver.Wrap(args, err))
+	}
+	return nil
+}
@@ -0,0 +1,234 @@
+package index
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/manojoshi/redisorm/driver"
+)
+
+// CopyOpt tunes Copy.
+type CopyOpt func(*copyCfg)
+
+type copyCfg struct {
+	batch      int
+	progress   func(Progress)
+	checkpoint string
+
+	rate          float64       // max keys/sec (0 = unthrottled)
+	maxPipeline   int           // max commands per round trip (0 = whole batch)
+	latencyTarget time.Duration // adapt rate to keep batches under this
+}
+
+// WithBatchSize sets the SCAN COUNT hint and pipeline size (default 500).
+func WithBatchSize(n int) CopyOpt { return func(c *copyCfg) { c.batch = n } }
+
+// WithProgress calls fn after every batch, and once more with Done set.
+func WithProgress(fn func(Progress)) CopyOpt { return func(c *copyCfg) { c.progress = fn } }
+
+// WithCheckpoint persists the scan position and counters under key after
+// every batch.  A later Copy with the same checkpoint key resumes where the
+// previous one stopped; the key is deleted once the copy completes.
+func WithCheckpoint(key string) CopyOpt { return func(c *copyCfg) { c.checkpoint = key } }
+
+// WithRateLimit caps the copy at keysPerSec, so a backfill can run against
+// production without starving foreground traffic.
+func WithRateLimit(keysPerSec float64) CopyOpt { return func(c *copyCfg) { c.rate = keysPerSec } }
+
+// WithMaxPipeline bounds how many COPY commands share one round trip.
+func WithMaxPipeline(n int) CopyOpt { return func(c *copyCfg) { c.maxPipeline = n } }
+
+// WithLatencyTarget makes the rate adaptive: whenever a pipelined round trip
+// takes longer than target the rate is halved, otherwise it creeps back up
+// (+10% per batch) towards the WithRateLimit ceiling.  Without a ceiling the
+// first measured throughput is used as one.
+func WithLatencyTarget(target time.Duration) CopyOpt {
+	return func(c *copyCfg) { c.latencyTarget = target }
+}
+
+// Progress is a snapshot of a running Copy.
+type Progress struct {
+	Processed int64         // keys visited (including failures)
+	Errors    int64         // keys that failed to copy
+	LastError error         // most recent per-key failure
+	Elapsed   time.Duration // wall time of this run
+	Rate      float64       // keys per second in this run
+	Limit     float64       // current throttle in keys/sec (0 = none)
+	Done      bool
+}
+
+// Copy duplicates every key under srcPrefix to the same suffix under
+// dstPrefix (COPY … REPLACE, so hashes and JSON documents both work).  It is
+// the data half of a reindex: Clone the index with the new prefix, then Copy
+// the documents into it.  Per-key failures are counted, not fatal; the
+// returned error is reserved for SCAN/transport failures and ctx expiry.
+func Copy(ctx context.Context, exec driver.Executor, srcPrefix, dstPrefix string, opts ...CopyOpt) (Progress, error) {
+	cfg := &copyCfg{batch: 500}
+	for _, o := range opts {
+		o(cfg)
+	}
+
+	var (
+		p      Progress
+		cursor = "0"
+		start  = time.Now()
+		base   int64 // processed before this run (resumed)
+		nested = strings.HasPrefix(dstPrefix, srcPrefix)
+		t      = &throttle{rate: cfg.rate, ceiling: cfg.rate, target: cfg.latencyTarget, last: time.Now()}
+	)
+	if cfg.checkpoint != "" {
+		var err error
+		if cursor, p, err = loadCheckpoint(ctx, exec, cfg.checkpoint); err != nil {
+			return p, err
+		}
+		base = p.Processed
+	}
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return p, err
+		}
+		raw, err := exec.Do(ctx, "SCAN", cursor, "MATCH", srcPrefix+"*", "COUNT", cfg.batch)
+		if err != nil {
+			return p, fmt.Errorf("index: copy scan: %w", driver.Classify(err))
+		}
+		next, keys, err := scanReply(raw)
+		if err != nil {
+			return p, err
+		}
+
+		if nested { // "order:" → "order:v2:" – don't re-copy our own output
+			kept := keys[:0]
+			for _, k := range keys {
+				if !strings.HasPrefix(k, dstPrefix) {
+					kept = append(kept, k)
+				}
+			}
+			keys = kept
+		}
+		for len(keys) > 0 {
+			chunk := keys
+			if cfg.maxPipeline > 0 && len(chunk) > cfg.maxPipeline {
+				chunk = keys[:cfg.maxPipeline]
+			}
+			keys = keys[len(chunk):]
+
+			cmds := make([][]interface{}, len(chunk))
+			for i, k := range chunk {
+				cmds[i] = []interface{}{"COPY", k, dstPrefix + strings.TrimPrefix(k, srcPrefix), "REPLACE"}
+			}
+			sent := time.Now()
+			replies, err := driver.DoBatch(ctx, exec, cmds)
+			if err != nil {
+				return p, fmt.Errorf("index: copy: %w", err)
+			}
+			for i, r := range replies {
+				if e, ok := r.(error); ok {
+					p.Errors++
+					p.LastError = fmt.Errorf("index: copy %s: %w", chunk[i], e)
+				}
+			}
+			p.Processed += int64(len(chunk))
+			if err := t.wait(ctx, len(chunk), time.Since(sent)); err != nil {
+				return p, err
+			}
+		}
+		p.Limit = t.rate
+
+		cursor = next
+		p.Elapsed = time.Since(start)
+		if secs := p.Elapsed.Seconds(); secs > 0 {
+			p.Rate = float64(p.Processed-base) / secs
+		}
+		p.Done = cursor == "0"
+
+		if cfg.checkpoint != "" {
+			if err := saveCheckpoint(ctx, exec, cfg.checkpoint, cursor, p); err != nil {
+				return p, err
+			}
+		}
+		if cfg.progress != nil {
+			cfg.progress(p)
+		}
+		if p.Done {
+			return p, nil
+		}
+	}
+}
+
+// throttle paces round trips to rate keys/sec, adapting rate to latency
+// when a target is set (additive increase, multiplicative decrease).
+type throttle struct {
+	rate, ceiling float64
+	target        time.Duration
+	last          time.Time
+}
+
+func (t *throttle) wait(ctx context.Context, n int, latency time.Duration) error {
+	if t.target > 0 {
+		if t.rate == 0 && t.ceiling == 0 { // calibrate from the first batch
+			t.ceiling = float64(n) / max(latency.Seconds(), 1e-3)
+			t.rate = t.ceiling
+		}
+		if latency > t.target {
+			t.rate = max(t.rate/2, 1)
+		} else {
+			t.rate = min(t.rate*1.1, t.ceiling)
+		}
+	}
+	if t.rate <= 0 {
+		return nil
+	}
+	due := t.last.Add(time.Duration(float64(n) / t.rate * float64(time.Second)))
+	t.last = due
+	if d := time.Until(due); d > 0 {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(d):
+		}
+	} else {
+		t.last = time.Now() // don't bank credit while we were slow
+	}
+	return nil
+}
+
+func scanReply(raw any) (string, []string, error) {
+	arr, ok := raw.([]interface{})
+	if !ok || len(arr) != 2 {
+		return "", nil, fmt.Errorf("index: unexpected SCAN reply %T", raw)
+	}
+	return str(arr[0]), strList(arr[1]), nil
+}
+
+func loadCheckpoint(ctx context.Context, exec driver.Executor, key string) (string, Progress, error) {
+	var p Progress
+	raw, err := exec.Do(ctx, "HGETALL", key)
+	if err != nil {
+		return "0", p, fmt.Errorf("index: read checkpoint: %w", err)
+	}
+	m := kvMap(raw)
+	cursor := str(m["cursor"])
+	if cursor == "" {
+		return "0", p, nil
+	}
+	p.Processed, _ = strconv.ParseInt(str(m["processed"]), 10, 64)
+	p.Errors, _ = strconv.ParseInt(str(m["errors"]), 10, 64)
+	return cursor, p, nil
+}
+
+func saveCheckpoint(ctx context.Context, exec driver.Executor, key, cursor string, p Progress) error {
+	var err error
+	if p.Done {
+		_, err = exec.Do(ctx, "DEL", key)
+	} else {
+		_, err = exec.Do(ctx, "HSET", key, "cursor", cursor, "processed", p.Processed, "errors", p.Errors)
+	}
+	if err != nil {
+		return fmt.Errorf("index: write checkpoint: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,11 @@
+package index
+
+import "github.com/manojoshi/redisorm/driver"
+
+// Re-exported from driver so callers of this package can test errors.Is
+// without importing driver.
+var (
+	ErrIndexNotFound = driver.ErrIndexNotFound
+	ErrIndexExists   = driver.ErrIndexExists
+	ErrTimeout       = driver.ErrTimeout
+)
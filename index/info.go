@@ -0,0 +1,300 @@
+package index
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/manojoshi/redisorm/driver"
+)
+
+// definition is the part of FT.INFO needed to re-create an index.
+type definition struct {
+	onJSON     bool
+	prefixes   []string
+	filter     string
+	language   string
+	langField  string
+	score      string
+	scoreField string
+	stopwords  []string
+	hasStop    bool // a custom stopword list was set (possibly empty)
+	fields     []fieldSpec
+}
+
+// IndexInfo is the FT.INFO reply of one index, parsed.
+type IndexInfo struct {
+	Name           string
+	OnJSON         bool     // ON JSON (else HASH)
+	Prefixes       []string // PREFIX
+	Filter         string   // FILTER expression, "" if none
+	Language       string   // default LANGUAGE
+	Options        []string // index_options: NOOFFSETS, NOFREQS, …
+	Stopwords      []string // custom STOPWORDS list; nil for the default
+	Fields         []FieldInfo
+	NumDocs        int64
+	NumTerms       int64
+	NumRecords     int64
+	Indexing       bool    // initial scan / reindex still running
+	PercentIndexed float64 // 0..1
+	Failures       int64   // documents that failed to index
+	LastError      string  // most recent indexing error, "" if none
+	MemoryMB       float64 // inverted index + doc table + sortables + key table + vectors
+}
+
+// FieldInfo describes one SCHEMA attribute.
+type FieldInfo struct {
+	Name  string   // attribute name used in queries
+	Path  string   // hash field / JSONPath, when it differs from Name
+	Type  string   // TEXT, TAG, NUMERIC, GEO, GEOSHAPE, VECTOR
+	Flags []string // SORTABLE, NOSTEM, …
+	Args  []string // WEIGHT 2, SEPARATOR ;, vector attributes, …
+}
+
+// Info calls FT.INFO name and parses the reply, RESP-2 or RESP-3.
+func Info(ctx context.Context, exec driver.Executor, name string) (*IndexInfo, error) {
+	top, err := ftInfo(ctx, exec, name)
+	if err != nil {
+		return nil, err
+	}
+	def := parseDefinition(top)
+	info := &IndexInfo{
+		Name:           name,
+		OnJSON:         def.onJSON,
+		Prefixes:       def.prefixes,
+		Filter:         def.filter,
+		Language:       def.language,
+		Options:        strList(top["index_options"]),
+		NumDocs:        int64(num(top["num_docs"])),
+		NumTerms:       int64(num(top["num_terms"])),
+		NumRecords:     int64(num(top["num_records"])),
+		Indexing:       num(top["indexing"]) != 0,
+		PercentIndexed: num(top["percent_indexed"]),
+		Failures:       int64(num(top["hash_indexing_failures"])),
+	}
+	if n := str(top["index_name"]); n != "" {
+		info.Name = n // the concrete index when name is an alias
+	}
+	if def.hasStop {
+		info.Stopwords = def.stopwords
+	}
+	for _, f := range def.fields {
+		fi := FieldInfo{Name: f.name, Path: f.path, Type: f.typ, Flags: f.flags, Args: f.args}
+		if f.typ == "VECTOR" {
+			fi.Args = append([]string{f.vectorAlgo}, f.vectorArgs...)
+		}
+		info.Fields = append(info.Fields, fi)
+	}
+	for _, k := range []string{"inverted_sz_mb", "doc_table_size_mb", "sortable_values_size_mb", "key_table_size_mb", "vector_index_sz_mb"} {
+		info.MemoryMB += num(top[k])
+	}
+	if errs := kvMap(top["Index Errors"]); errs != nil {
+		if info.LastError = str(errs["last indexing error"]); info.LastError == "N/A" {
+			info.LastError = ""
+		}
+	}
+	return info, nil
+}
+
+// ftInfo sends FT.INFO and returns the top-level reply as a map.
+func ftInfo(ctx context.Context, exec driver.Executor, name string) (map[string]any, error) {
+	args := []interface{}{"FT.INFO", name}
+	raw, err := exec.Do(ctx, args...)
+	if err != nil {
+		return nil, driver.Wrap(args, err)
+	}
+	top := kvMap(raw)
+	if top == nil {
+		return nil, fmt.Errorf("index: unexpected FT.INFO reply %T", raw)
+	}
+	return top, nil
+}
+
+// readDefinition calls FT.INFO name and extracts its definition.
+func readDefinition(ctx context.Context, exec driver.Executor, name string) (*definition, error) {
+	top, err := ftInfo(ctx, exec, name)
+	if err != nil {
+		return nil, err
+	}
+	return parseDefinition(top), nil
+}
+
+func parseDefinition(top map[string]any) *definition {
+	def := &definition{}
+	if d := kvMap(top["index_definition"]); d != nil {
+		def.onJSON = strings.EqualFold(str(d["key_type"]), "JSON")
+		def.prefixes = strList(d["prefixes"])
+		def.filter = str(d["filter"])
+		def.language = str(d["default_language"])
+		def.langField = str(d["language_field"])
+		def.score = str(d["default_score"])
+		def.scoreField = str(d["score_field"])
+	}
+	if sw, ok := top["stopwords_list"]; ok {
+		def.stopwords, def.hasStop = strList(sw), true
+	}
+	for _, a := range list(top["attributes"]) {
+		def.fields = append(def.fields, parseAttribute(a))
+	}
+	return def
+}
+
+// Prefixes returns the key prefixes an index covers (PREFIX in FT.CREATE).
+func Prefixes(ctx context.Context, exec driver.Executor, name string) ([]string, error) {
+	info, err := Info(ctx, exec, name)
+	if err != nil {
+		return nil, err
+	}
+	return info.Prefixes, nil
+}
+
+// parseAttribute turns one FT.INFO attribute entry into a fieldSpec.  RESP-2
+// sends a flat list mixing key/value pairs with bare flags; RESP-3 sends a
+// map with a "flags" array.
+func parseAttribute(a any) fieldSpec {
+	var (
+		spec   fieldSpec
+		vector []string
+	)
+	set := func(k, v string) {
+		switch strings.ToLower(k) {
+		case "identifier":
+			spec.path = v
+		case "attribute":
+			spec.name = v
+		case "type":
+			spec.typ = strings.ToUpper(v)
+		case "weight":
+			if v != "1" {
+				spec.args = append(spec.args, "WEIGHT", v)
+			}
+		case "separator":
+			if v != "," {
+				spec.args = append(spec.args, "SEPARATOR", v)
+			}
+		case "phonetic":
+			spec.args = append(spec.args, "PHONETIC", v)
+		case "algorithm", "data_type", "dim", "distance_metric", "m", "ef_construction":
+			key := strings.ToUpper(k)
+			switch key {
+			case "DATA_TYPE":
+				key = "TYPE"
+			case "ALGORITHM":
+				spec.vectorAlgo = strings.ToUpper(v)
+				return
+			}
+			vector = append(vector, key, v)
+		}
+	}
+
+	switch v := a.(type) {
+	case []interface{}:
+		for i := 0; i < len(v); i++ {
+			k := str(v[i])
+			if isFlag(k) {
+				spec.flags = append(spec.flags, strings.ToUpper(k))
+				continue
+			}
+			if i+1 < len(v) {
+				if sub, ok := v[i+1].([]interface{}); ok { // nested vector params (older servers)
+					for j := 0; j+1 < len(sub); j += 2 {
+						set(str(sub[j]), str(sub[j+1]))
+					}
+				} else {
+					set(k, str(v[i+1]))
+				}
+				i++
+			}
+		}
+	default:
+		m := kvMap(a)
+		for k, val := range m {
+			if k == "flags" {
+				for _, f := range strList(val) {
+					spec.flags = append(spec.flags, strings.ToUpper(f))
+				}
+				continue
+			}
+			set(k, str(val))
+		}
+	}
+	if spec.name == "" {
+		spec.name = spec.path
+	}
+	if spec.path == spec.name {
+		spec.path = ""
+	}
+	spec.vectorArgs = vector
+	return spec
+}
+
+func isFlag(s string) bool {
+	switch strings.ToUpper(s) {
+	case "SORTABLE", "UNF", "NOSTEM", "NOINDEX", "CASESENSITIVE",
+		"WITHSUFFIXTRIE", "INDEXEMPTY", "INDEXMISSING":
+		return true
+	}
+	return false
+}
+
+// ---------------------------------------------------------------------
+// reply helpers – FT.INFO comes back as flat lists (RESP-2) or maps (RESP-3)
+// ---------------------------------------------------------------------
+
+func kvMap(v any) map[string]any {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		return t
+	case map[interface{}]interface{}:
+		m := make(map[string]any, len(t))
+		for k, val := range t {
+			m[str(k)] = val
+		}
+		return m
+	case []interface{}:
+		m := make(map[string]any, len(t)/2)
+		for i := 0; i+1 < len(t); i += 2 {
+			m[str(t[i])] = t[i+1]
+		}
+		return m
+	}
+	return nil
+}
+
+func list(v any) []any {
+	l, _ := v.([]interface{})
+	return l
+}
+
+func strList(v any) []string {
+	l := list(v)
+	out := make([]string, len(l))
+	for i, x := range l {
+		out[i] = str(x)
+	}
+	return out
+}
+
+func str(v any) string {
+	switch t := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return t
+	case []byte:
+		return string(t)
+	case int64:
+		return strconv.FormatInt(t, 10)
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64)
+	default:
+		return fmt.Sprint(t)
+	}
+}
+
+// num reads a counter that FT.INFO sends as a number or a numeric string.
+func num(v any) float64 {
+	f, _ := strconv.ParseFloat(strings.TrimSpace(str(v)), 64)
+	return f
+}
@@ -19,13 +19,22 @@ package index
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"reflect"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/manojoshi/redisorm/driver"
+	"github.com/manojoshi/redisorm/scan"
 )
 
+// ShadowSuffix names the NUMERIC sibling written next to a SHADOW field:
+// an exact big.Rat/decimal stored as "amount" is indexed as "amount_num".
+const ShadowSuffix = "_num"
+
 // ------------------------------------------------------------------
 // Options
 // ------------------------------------------------------------------
@@ -37,6 +46,19 @@ type createCfg struct {
 	prefixes  []string // HASH/JSON key prefixes
 	onJson    bool     // ON JSON (default: HASH)
 	stopwords []string
+	models    []any // extra models merged into the schema
+
+	additiveOnly  bool          // existing index: add new fields, refuse anything else
+	maxTextFields bool          // MAXTEXTFIELDS: room for more than 32 TEXT fields
+	temporary     time.Duration // TEMPORARY: drop after this long unused
+	skipScan      bool          // SKIPINITIALSCAN: index only new writes
+
+	// WithFilter, WithLanguage, …; carried over by Clone from the source
+	filter        string
+	language      string
+	languageField string
+	score         string
+	scoreField    string
 }
 
 func WithName(name string) CreateOpt          { return func(c *createCfg) { c.name = name } }
@@ -44,13 +66,73 @@ func WithPrefixes(p ...string) CreateOpt      { return func(c *createCfg) { c.pr
 func OnJSON() CreateOpt                       { return func(c *createCfg) { c.onJson = true } }
 func WithStopwords(words ...string) CreateOpt { return func(c *createCfg) { c.stopwords = words } }
 
+// WithFilter indexes only the documents matching a RediSearch aggregation
+// expression, for conditional indexes over a shared prefix:
+//
+//	index.WithFilter("@status=='ACTIVE'")
+func WithFilter(expr string) CreateOpt { return func(c *createCfg) { c.filter = expr } }
+
+// WithLanguage sets the default stemming language ("english", "german", …).
+func WithLanguage(lang string) CreateOpt { return func(c *createCfg) { c.language = lang } }
+
+// WithLanguageField names the document field holding each document's own
+// language, for multilingual indexes.
+func WithLanguageField(field string) CreateOpt {
+	return func(c *createCfg) { c.languageField = field }
+}
+
+// WithScore sets the default document score, 0..1 (RediSearch uses 1).
+func WithScore(score float64) CreateOpt {
+	return func(c *createCfg) { c.score = strconv.FormatFloat(score, 'f', -1, 64) }
+}
+
+// WithScoreField names the document field holding each document's score.
+func WithScoreField(field string) CreateOpt {
+	return func(c *createCfg) { c.scoreField = field }
+}
+
+// WithMaxTextFields lets the index grow past 32 TEXT fields (MAXTEXTFIELDS);
+// needed up front when later FT.ALTERs may add that many.
+func WithMaxTextFields() CreateOpt { return func(c *createCfg) { c.maxTextFields = true } }
+
+// WithTemporary makes a scratch index that RediSearch drops by itself after
+// ttl without queries (TEMPORARY, whole seconds), for ad-hoc analytics jobs
+// that may not clean up after themselves.
+func WithTemporary(ttl time.Duration) CreateOpt {
+	return func(c *createCfg) { c.temporary = ttl }
+}
+
+// SkipInitialScan creates the index without scanning the existing keyspace
+// (SKIPINITIALSCAN): only documents written afterwards are indexed.  Use it
+// when a deploy must not block on a huge keyspace and backfill separately.
+func SkipInitialScan() CreateOpt { return func(c *createCfg) { c.skipScan = true } }
+
+// AdditiveOnly turns AutoCreate into a safe schema sync for deployment
+// pipelines: when the index already exists, fields new in the model are added
+// with FT.ALTER, while type changes or removed fields abort with a
+// *SchemaConflictError listing every difference.  See Alter.
+func AdditiveOnly() CreateOpt { return func(c *createCfg) { c.additiveOnly = true } }
+
+// WithModels adds more models to the index: their schemas are merged with
+// the main model's (see MergeSchema).  Pair it with one prefix per model:
+//
+//	index.AutoCreate(ctx, conn, Order{},
+//	    index.WithName("everything_idx"),
+//	    index.WithModels(Customer{}, Product{}),
+//	    index.WithPrefixes("order:", "customer:", "product:"),
+//	)
+func WithModels(models ...any) CreateOpt {
+	return func(c *createCfg) { c.models = append(c.models, models...) }
+}
+
 // ------------------------------------------------------------------
 // Public API
 // ------------------------------------------------------------------
 
 // AutoCreate builds a schema from the supplied struct model and invokes
 // FT.CREATE IF NOT EXISTS.  It is safe to call concurrently – Redis will just
-// return an error we ignore when the index already exists.
+// return an error we ignore when the index already exists.  Models with
+// malformed tags are rejected up front (see ValidateModel).
 func AutoCreate(
 	ctx context.Context,
 	exec driver.Executor,
@@ -62,8 +144,37 @@ func AutoCreate(
 	for _, o := range opts {
 		o(cfg)
 	}
+	err := create(ctx, exec, cfg, model)
+	if errors.Is(err, ErrIndexExists) {
+		if !cfg.additiveOnly {
+			return nil
+		}
+		_, err = alter(ctx, exec, cfg, model)
+	}
+	return err
+}
 
-	schemaArgs := BuildSchema(model)
+// create validates the models and sends FT.CREATE; an existing index is
+// reported as ErrIndexExists.
+func create(ctx context.Context, exec driver.Executor, cfg *createCfg, model any) error {
+	for _, m := range append([]any{model}, cfg.models...) {
+		if err := ValidateModel(m); err != nil {
+			return err
+		}
+	}
+	fields, err := wantedFields(cfg, model)
+	if err != nil {
+		return err
+	}
+	args := createArgs(cfg, flatten(fields))
+	if _, err := exec.Do(ctx, args...); err != nil {
+		return fmt.Errorf("index: FT.CREATE failed: %w", driver.Wrap(args, err))
+	}
+	return nil
+}
+
+// createArgs assembles FT.CREATE from the options and a SCHEMA tail.
+func createArgs(cfg *createCfg, schemaArgs []interface{}) []interface{} {
 	args := []interface{}{"FT.CREATE", cfg.name}
 	if cfg.onJson {
 		args = append(args, "ON", "JSON")
@@ -74,71 +185,359 @@ func AutoCreate(
 			args = append(args, p)
 		}
 	}
-	if len(cfg.stopwords) > 0 {
+	if cfg.filter != "" {
+		args = append(args, "FILTER", cfg.filter)
+	}
+	if cfg.language != "" {
+		args = append(args, "LANGUAGE", cfg.language)
+	}
+	if cfg.languageField != "" {
+		args = append(args, "LANGUAGE_FIELD", cfg.languageField)
+	}
+	if cfg.score != "" {
+		args = append(args, "SCORE", cfg.score)
+	}
+	if cfg.scoreField != "" {
+		args = append(args, "SCORE_FIELD", cfg.scoreField)
+	}
+	if cfg.maxTextFields {
+		args = append(args, "MAXTEXTFIELDS")
+	}
+	if cfg.temporary > 0 {
+		args = append(args, "TEMPORARY", int64(max(cfg.temporary.Seconds(), 1)))
+	}
+	if cfg.stopwords != nil { // empty but non-nil: STOPWORDS 0 disables them
 		args = append(args, "STOPWORDS", len(cfg.stopwords))
 		for _, s := range cfg.stopwords {
 			args = append(args, s)
 		}
 	}
+	if cfg.skipScan {
+		args = append(args, "SKIPINITIALSCAN")
+	}
 	args = append(args, "SCHEMA")
-	args = append(args, schemaArgs...)
+	return append(args, schemaArgs...)
+}
+
+// BuildSchema inspects the struct tags (`redisorm:\"@field,TAG,SORTABLE\"`) and
+// returns the tail of the SCHEMA clause as []interface{}.  It cannot fail, so a
+// slice VECTOR field without DIM is left for ValidateModel (or MergeSchema)
+// to report.
+func BuildSchema(model any) []interface{} {
+	return flatten(schemaFields(model))
+}
+
+// MergeSchema builds one SCHEMA clause covering several models, for indexes
+// spanning related entities (orders, customers, …) under different prefixes.
+// A field declared by more than one model must have the same type
+// everywhere; its attributes (SORTABLE, …) are unioned, except NOINDEX,
+// which is kept only when every model declaring the field sets it (a PK in
+// one model may be a searchable reference in another).  Conflicts and
+// slice VECTOR fields without DIM are all reported together.
+func MergeSchema(models ...any) ([]interface{}, error) {
+	fields, err := mergeFields(models)
+	if err != nil {
+		return nil, err
+	}
+	return flatten(fields), nil
+}
+
+// BuildJSONSchema is BuildSchema for ON JSON indexes: every field is read
+// from its top-level JSONPath ("$.qty AS qty"), []string fields from their
+// array elements ("$.labels[*] AS labels").
+func BuildJSONSchema(model any) []interface{} {
+	return flatten(jsonPaths(schemaFields(model)))
+}
+
+// jsonPaths gives fields without an explicit path their JSONPath.
+func jsonPaths(fields []fieldSpec) []fieldSpec {
+	out := slices.Clone(fields)
+	for i := range out {
+		f := &out[i]
+		if f.path != "" {
+			continue
+		}
+		f.path = f.jsonPath
+		if f.path == "" {
+			f.path = "$." + f.name
+		}
+		if f.list {
+			f.path += "[*]"
+			f.args = nil // JSON arrays need no SEPARATOR
+		}
+	}
+	return out
+}
+
+// wantedFields are the SCHEMA fields of model and cfg's WithModels, with
+// their JSONPaths on OnJSON indexes: what AutoCreate creates and Alter adds.
+func wantedFields(cfg *createCfg, model any) ([]fieldSpec, error) {
+	fields, err := mergeFields(append([]any{model}, cfg.models...))
+	if err != nil {
+		return nil, err
+	}
+	if cfg.onJson {
+		fields = jsonPaths(fields)
+	}
+	return fields, nil
+}
 
-	if _, err := exec.Do(ctx, args...); err != nil &&
-		!strings.Contains(err.Error(), "Index already exists") {
-		return fmt.Errorf("index: FT.CREATE failed: %w", err)
+func mergeFields(models []any) ([]fieldSpec, error) {
+	var (
+		merged []fieldSpec
+		owner  = map[string]int{} // field name → position in merged
+		from   = map[string]string{}
+		shared = map[string]bool{} // field indexed by at least one model
+		errs   []error
+	)
+	for _, m := range models {
+		model := typeName(m)
+		for _, f := range schemaFields(m) {
+			if err := f.check(model); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			if !slices.Contains(f.flags, "NOINDEX") {
+				shared[f.name] = true
+			}
+			i, seen := owner[f.name]
+			if !seen {
+				owner[f.name], from[f.name] = len(merged), model
+				merged = append(merged, f)
+				continue
+			}
+			if merged[i].typ != f.typ {
+				errs = append(errs, fmt.Errorf("index: field %q is %s in %s but %s in %s",
+					f.name, merged[i].typ, from[f.name], f.typ, model))
+				continue
+			}
+			for _, a := range f.flags {
+				if !slices.Contains(merged[i].flags, a) {
+					merged[i].flags = append(merged[i].flags, a)
+				}
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	for i, f := range merged { // one model's PK must not hide another's field
+		if shared[f.name] {
+			merged[i].flags = slices.DeleteFunc(slices.Clone(f.flags), func(a string) bool { return a == "NOINDEX" })
+		}
+	}
+	return merged, nil
+}
+
+// fieldSpec is one parsed SCHEMA entry.
+type fieldSpec struct {
+	path       string   // JSONPath / hash field when it differs from name ("identifier AS name")
+	name       string   // attribute name used in queries
+	typ        string   // TEXT, TAG, NUMERIC, GEO, GEOSHAPE, VECTOR
+	vectorAlgo string   // FLAT / HNSW (VECTOR only)
+	vectorArgs []string // TYPE FLOAT32 DIM 768 … (VECTOR only)
+	args       []string // valued options: WEIGHT 2, SEPARATOR ;, …
+	flags      []string // bare options: SORTABLE, NOSTEM, NOINDEX, …
+	list       bool     // []string TAG field
+	jsonPath   string   // "$.audit.created_ts", used ON JSON
+}
+
+// check reports a field spec FT.CREATE would reject: a slice VECTOR field
+// has no length to infer DIM from, so its tag must give one.
+func (f fieldSpec) check(model string) error {
+	if f.typ == "VECTOR" && !slices.Contains(f.vectorArgs, "DIM") {
+		return fmt.Errorf("index: VECTOR field %q of %s needs DIM=n (or an array type)", f.name, model)
 	}
 	return nil
 }
 
-// BuildSchema inspects the struct tags (`redisorm:\"@field,TAG,SORTABLE\"`) and
-// returns the tail of the SCHEMA clause as []interface{}.
-func BuildSchema(model any) []interface{} {
+func schemaFields(model any) []fieldSpec {
 	rt := reflect.TypeOf(model)
 	if rt.Kind() == reflect.Pointer {
 		rt = rt.Elem()
 	}
 
-	var out []interface{}
-	for i := 0; i < rt.NumField(); i++ {
-		f := rt.Field(i)
-		tag := f.Tag.Get("redisorm")
-		if tag == "" {
+	var out []fieldSpec
+	for _, sf := range scan.Fields(rt) { // embedded / nested fragments flattened
+		if scan.MetaField(sf.Name) {
 			continue
 		}
+		f, tag := sf.Struct, sf.Tag
 		parts := strings.Split(tag, ",")
-		name := strings.TrimPrefix(parts[0], "@")
-		fieldType := "TEXT" // default
+		spec := fieldSpec{name: sf.Name, typ: "TEXT"} // default
+		spec.jsonPath = "$." + strings.Join(sf.JSON, ".")
+		if ft := f.Type; ft == timeType || ft.Kind() == reflect.Pointer && ft.Elem() == timeType {
+			spec.typ = "NUMERIC" // stored as unix seconds / millis, see scan.CodecForField
+		}
+		if ft := f.Type; ft == stringsType || ft.Kind() == reflect.Pointer && ft.Elem() == stringsType {
+			spec.typ, spec.list = "TAG", true // joined multi-value, see scan.TagSeparator
+		}
 
-		// extra attributes (NUMERIC, TAG, GEO, SORTABLE, PK)
+		// extra attributes (NUMERIC, TAG, GEO, GEOSHAPE, SORTABLE, PK, SHADOW)
 		attrs := parts[1:]
+		shadow := false
 		for _, a := range attrs {
 			switch strings.ToUpper(a) {
-			case "NUMERIC", "TAG", "GEO", "VECTOR":
-				fieldType = strings.ToUpper(a)
+			case "NUMERIC", "TAG", "GEO", "GEOSHAPE", "VECTOR":
+				spec.typ = strings.ToUpper(a)
+			case "SHADOW":
+				shadow = true
 			}
 		}
+		if shadow {
+			// exact value stays unindexed; its float sibling is searchable
+			spec.name, spec.typ = spec.name+ShadowSuffix, "NUMERIC"
+			spec.jsonPath += ShadowSuffix
+		}
 
-		out = append(out, name, fieldType)
 		for _, a := range attrs {
 			upper := strings.ToUpper(a)
 			switch upper {
 			case "SORTABLE", "NOINDEX", "NOSTEM":
-				out = append(out, upper)
+				spec.flags = append(spec.flags, upper)
+			case "WITHSUFFIXTRIE":
+				if spec.typ == "TEXT" || spec.typ == "TAG" {
+					spec.flags = append(spec.flags, upper)
+				}
 			case "PK":
-				out = append(out, "NOINDEX")
+				spec.flags = append(spec.flags, "NOINDEX")
+			case "CASESENSITIVE":
+				if spec.typ == "TAG" {
+					spec.flags = append(spec.flags, upper)
+				}
 			}
+			// TEXT relevance tuning: WEIGHT=2.0, PHONETIC=dm:en
+			if k, v, ok := strings.Cut(a, "="); ok && spec.typ == "TEXT" && textAttrs[strings.ToUpper(k)] {
+				if w, err := strconv.ParseFloat(v, 64); err == nil {
+					v = strconv.FormatFloat(w, 'f', -1, 64) // "2.0" reads back from FT.INFO as "2"
+				}
+				spec.args = append(spec.args, strings.ToUpper(k), v)
+			}
+		}
+		if spec.typ == "VECTOR" {
+			vectorParams(&spec, f.Type, attrs)
 		}
+		// TAG SEPARATOR=; (scan splits []string fields on the same one)
+		if sep := scan.TagSeparator(tag); spec.typ == "TAG" && sep != "," {
+			spec.args = append(spec.args, "SEPARATOR", sep)
+		}
+		out = append(out, spec)
 	}
 	return out
 }
 
-// inferIndexName defaults to struct type name snake_cased + \"_idx\".
-func inferIndexName(model any) string {
+// vectorParams fills the algorithm and attribute list of a VECTOR field:
+//
+//	`redisorm:"@embedding,VECTOR,HNSW,DIM=768,TYPE=FLOAT32,DISTANCE_METRIC=COSINE"`
+//
+// The algorithm defaults to FLAT, TYPE to FLOAT32 (FLOAT64 for a float64
+// element type) and DISTANCE_METRIC to COSINE; DIM is inferred from array
+// fields such as [768]float32.
+func vectorParams(spec *fieldSpec, ft reflect.Type, attrs []string) {
+	spec.vectorAlgo = "FLAT"
+	params := map[string]string{}
+	var order []string
+	for _, a := range attrs {
+		k, v, ok := strings.Cut(a, "=")
+		k = strings.ToUpper(k)
+		switch {
+		case !ok && (k == "FLAT" || k == "HNSW"):
+			spec.vectorAlgo = k
+		case ok && vectorAttrs[k]:
+			if _, dup := params[k]; !dup {
+				order = append(order, k)
+			}
+			params[k] = strings.ToUpper(v)
+		}
+	}
+
+	elem := ft
+	if elem.Kind() == reflect.Array || elem.Kind() == reflect.Slice {
+		elem = elem.Elem()
+	}
+	defaults := []struct{ k, v string }{{"TYPE", "FLOAT32"}, {"DISTANCE_METRIC", "COSINE"}}
+	if elem.Kind() == reflect.Float64 {
+		defaults[0].v = "FLOAT64"
+	}
+	if ft.Kind() == reflect.Array {
+		defaults = append([]struct{ k, v string }{{"DIM", strconv.Itoa(ft.Len())}}, defaults...)
+	}
+	for _, d := range defaults {
+		if _, ok := params[d.k]; !ok {
+			params[d.k] = d.v
+			order = append(order, d.k)
+		}
+	}
+	for _, k := range order {
+		spec.vectorArgs = append(spec.vectorArgs, k, params[k])
+	}
+}
+
+var (
+	timeType    = reflect.TypeOf(time.Time{})
+	stringsType = reflect.TypeOf([]string(nil))
+)
+
+// textAttrs are the KEY=VALUE tag attributes passed to a TEXT field.
+var textAttrs = map[string]bool{"WEIGHT": true, "PHONETIC": true}
+
+// vectorAttrs are the KEY=VALUE tag attributes passed to a VECTOR field.
+var vectorAttrs = map[string]bool{
+	"TYPE": true, "DIM": true, "DISTANCE_METRIC": true, "INITIAL_CAP": true,
+	"BLOCK_SIZE": true, "M": true, "EF_CONSTRUCTION": true, "EF_RUNTIME": true,
+	"EPSILON": true,
+}
+
+func flatten(fields []fieldSpec) []interface{} {
+	var out []interface{}
+	for _, f := range fields {
+		if f.path != "" {
+			out = append(out, f.path, "AS")
+		}
+		out = append(out, f.name, f.typ)
+		if f.typ == "VECTOR" {
+			out = append(out, f.vectorAlgo, len(f.vectorArgs))
+			for _, a := range f.vectorArgs {
+				out = append(out, a)
+			}
+		}
+		for _, a := range f.args {
+			out = append(out, a)
+		}
+		// SORTABLE [UNF] must close the field definition
+		var tail []interface{}
+		for _, a := range f.flags {
+			if a == "SORTABLE" || a == "UNF" {
+				tail = append(tail, a)
+				continue
+			}
+			out = append(out, a)
+		}
+		out = append(out, tail...)
+	}
+	return out
+}
+
+func typeName(model any) string {
 	t := reflect.TypeOf(model)
 	if t.Kind() == reflect.Pointer {
 		t = t.Elem()
 	}
-	return snake(t.Name()) + "_idx"
+	return t.Name()
+}
+
+// NameFor is the index name AutoCreate, Alter and repository.ForModel use
+// for model when none is given: the type name snake_cased plus "_idx"
+// (OrderLine → "order_line_idx").
+func NameFor(model any) string { return inferIndexName(model) }
+
+// PrefixFor is the key prefix paired with NameFor: the snake_cased type
+// name plus ":" (OrderLine → "order_line:").
+func PrefixFor(model any) string { return snake(typeName(model)) + ":" }
+
+// inferIndexName defaults to struct type name snake_cased + \"_idx\".
+func inferIndexName(model any) string {
+	return snake(typeName(model)) + "_idx"
 }
 
 // snake converts CamelCase to snake_case.
@@ -0,0 +1,107 @@
+package index
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/manojoshi/redisorm/driver"
+)
+
+// Synonyms manages the synonym groups of one index (FT.SYNUPDATE /
+// FT.SYNDUMP).  Terms in a group match each other in full-text queries:
+//
+//	syn := index.NewSynonyms(conn, "order_idx")
+//	_ = syn.AddGroup(ctx, "shipping", []string{"shipped", "dispatched", "sent"})
+//	groups, _ := syn.Dump(ctx)
+type Synonyms struct {
+	exec  driver.Executor
+	index string
+}
+
+// NewSynonyms binds the synonym groups of index name.
+func NewSynonyms(exec driver.Executor, name string) *Synonyms {
+	return &Synonyms{exec: exec, index: name}
+}
+
+// SynonymGroup is one group as FT.SYNDUMP reports it.
+type SynonymGroup struct {
+	ID    string
+	Terms []string // sorted
+}
+
+// SynOpt tunes AddGroup.
+type SynOpt func(*synCfg)
+
+type synCfg struct{ skipScan bool }
+
+// SynSkipInitialScan applies the group to documents indexed from now on
+// only, instead of re-scanning the existing ones (SKIPINITIALSCAN).
+func SynSkipInitialScan() SynOpt { return func(c *synCfg) { c.skipScan = true } }
+
+// AddGroup adds terms to group id, creating it if needed.  Terms already in
+// the group are kept; there is no command to remove them.
+func (s *Synonyms) AddGroup(ctx context.Context, id string, terms []string, opts ...SynOpt) error {
+	if len(terms) == 0 {
+		return fmt.Errorf("index: synonym group %q: no terms", id)
+	}
+	cfg := &synCfg{}
+	for _, o := range opts {
+		o(cfg)
+	}
+	args := []interface{}{"FT.SYNUPDATE", s.index, id}
+	if cfg.skipScan {
+		args = append(args, "SKIPINITIALSCAN")
+	}
+	for _, t := range terms {
+		args = append(args, t)
+	}
+	if _, err := s.exec.Do(ctx, args...); err != nil {
+		return driver.Wrap(args, err)
+	}
+	return nil
+}
+
+// Dump lists the index's synonym groups, sorted by ID.
+func (s *Synonyms) Dump(ctx context.Context) ([]SynonymGroup, error) {
+	args := []interface{}{"FT.SYNDUMP", s.index}
+	raw, err := s.exec.Do(ctx, args...)
+	if err != nil {
+		return nil, driver.Wrap(args, err)
+	}
+	return parseSynDump(raw), nil
+}
+
+// Groups is Dump keyed by term: the group IDs each term belongs to, as
+// FT.SYNDUMP itself reports them.
+func (s *Synonyms) Groups(ctx context.Context) (map[string][]string, error) {
+	args := []interface{}{"FT.SYNDUMP", s.index}
+	raw, err := s.exec.Do(ctx, args...)
+	if err != nil {
+		return nil, driver.Wrap(args, err)
+	}
+	out := map[string][]string{}
+	for term, ids := range kvMap(raw) {
+		out[term] = strList(ids)
+	}
+	return out, nil
+}
+
+// parseSynDump inverts the term → group IDs reply (flat list on RESP-2,
+// map on RESP-3) into groups.
+func parseSynDump(raw any) []SynonymGroup {
+	byID := map[string][]string{}
+	for term, ids := range kvMap(raw) {
+		for _, id := range strList(ids) {
+			byID[id] = append(byID[id], term)
+		}
+	}
+	out := make([]SynonymGroup, 0, len(byID))
+	for id, terms := range byID {
+		slices.Sort(terms)
+		out = append(out, SynonymGroup{ID: id, Terms: terms})
+	}
+	slices.SortFunc(out, func(a, b SynonymGroup) int { return strings.Compare(a.ID, b.ID) })
+	return out
+}
@@ -0,0 +1,24 @@
+package index
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/manojoshi/redisorm/driver"
+)
+
+// TagVals returns the distinct values of a TAG field, sorted – handy for
+// filter dropdowns without running an aggregation.  RediSearch stores tags
+// lower-cased unless the field is CASESENSITIVE, and the list covers every
+// indexed document; there is no way to filter it by query.
+func TagVals(ctx context.Context, exec driver.Executor, indexName, field string) ([]string, error) {
+	args := []interface{}{"FT.TAGVALS", indexName, strings.TrimPrefix(field, "@")}
+	raw, err := exec.Do(ctx, args...)
+	if err != nil {
+		return nil, driver.Wrap(args, err)
+	}
+	vals := strList(raw)
+	slices.Sort(vals)
+	return vals, nil
+}
@@ -0,0 +1,260 @@
+package index
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/manojoshi/redisorm/scan"
+)
+
+// TagError is one malformed redisorm tag found by ValidateModel.
+type TagError struct {
+	Model   string // "Order"
+	Field   string // Go field name, "Qty"
+	Tag     string // the redisorm tag as written
+	Problem string
+}
+
+func (e *TagError) Error() string {
+	return fmt.Sprintf("index: %s.%s `redisorm:%q`: %s", e.Model, e.Field, e.Tag, e.Problem)
+}
+
+// ValidateModel checks the redisorm tags of model before they become a
+// schema: unknown or conflicting attributes, duplicate field names,
+// SORTABLE on VECTOR/GEOSHAPE, VECTOR fields without a usable DIM, … .
+// BuildSchema quietly ignores such mistakes and yields an index that does
+// not match the model; AutoCreate calls ValidateModel first instead.
+//
+// Every offending field is reported, as *TagError values joined with
+// errors.Join; nil means the tags are sound.
+func ValidateModel(model any) error {
+	rt := reflect.TypeOf(model)
+	if rt != nil && rt.Kind() == reflect.Pointer {
+		rt = rt.Elem()
+	}
+	if rt == nil || rt.Kind() != reflect.Struct {
+		return fmt.Errorf("index: model must be a struct, got %T", model)
+	}
+
+	var (
+		errs  []error
+		names = map[string]string{} // indexed name → Go field that claimed it
+		pk    string
+		ver   string
+	)
+	for _, f := range scan.Fields(rt) {
+		if scan.MetaField(f.Name) {
+			continue
+		}
+		report := func(format string, args ...any) {
+			errs = append(errs, &TagError{
+				Model:   rt.Name(),
+				Field:   f.Struct.Name,
+				Tag:     f.Struct.Tag.Get("redisorm"),
+				Problem: fmt.Sprintf(format, args...),
+			})
+		}
+		for _, p := range tagProblems(f) {
+			report("%s", p)
+		}
+
+		name := f.Name
+		if hasFlag(f.Attrs, "SHADOW") {
+			name += ShadowSuffix
+		}
+		if prev, dup := names[name]; dup {
+			report("field name %q is already used by %s", name, prev)
+		}
+		names[name] = f.Struct.Name
+		if hasFlag(f.Attrs, "PK") {
+			if pk != "" {
+				report("second PK field (%s is already the PK)", pk)
+			}
+			pk = f.Struct.Name
+		}
+		if hasFlag(f.Attrs, "VERSION") {
+			if ver != "" {
+				report("second VERSION field (%s is already the version)", ver)
+			}
+			ver = f.Struct.Name
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// tagProblems lists what is wrong with a single field's tag.
+func tagProblems(f scan.Field) []string {
+	var out []string
+	if f.Name == "" || strings.HasSuffix(f.Name, "_") {
+		out = append(out, "missing field name (want `redisorm:\"@name,…\"`)")
+	}
+
+	var types []string
+	for _, a := range f.Attrs {
+		k, v, valued := strings.Cut(a, "=")
+		k = strings.ToUpper(strings.TrimSpace(k))
+		switch {
+		case a == "":
+			out = append(out, "empty attribute (stray comma)")
+		case valued && v == "":
+			out = append(out, fmt.Sprintf("%s= has no value", k))
+		case !valued && fieldTypes[k]:
+			types = append(types, k)
+		case !valued && tagFlags[k], valued && tagValues[k], valued && vectorAttrs[k]:
+		default:
+			out = append(out, fmt.Sprintf("unknown attribute %q", a))
+		}
+	}
+	if len(types) > 1 {
+		out = append(out, "conflicting field types "+strings.Join(types, ", "))
+	}
+
+	typ := "TEXT"
+	if len(types) > 0 {
+		typ = types[0]
+	}
+	ft := f.Struct.Type
+	if ft.Kind() == reflect.Pointer {
+		ft = ft.Elem()
+	}
+	switch {
+	case ft == timeType && len(types) == 0:
+		typ = "NUMERIC"
+	case ft == stringsType:
+		typ = "TAG"
+		if len(types) > 0 && types[0] != "TAG" {
+			out = append(out, fmt.Sprintf("[]string fields are TAG, not %s", types[0]))
+		}
+	}
+	if hasFlag(f.Attrs, "SHADOW") {
+		c, _ := scan.CodecForField(f.Struct)
+		if _, numeric := c.(scan.NumericCodec); !numeric {
+			out = append(out, "SHADOW needs a type with a numeric codec (big.Rat, decimal, …)")
+		} else {
+			typ = "NUMERIC"
+		}
+	}
+
+	if hasFlag(f.Attrs, "SORTABLE") && (typ == "VECTOR" || typ == "GEOSHAPE") {
+		out = append(out, "SORTABLE is not supported on "+typ+" fields")
+	}
+	if typ == "NUMERIC" && !numericKind(f.Struct) {
+		out = append(out, fmt.Sprintf("NUMERIC field has type %s, which is not stored as a number", f.Struct.Type))
+	}
+	for _, a := range f.Attrs {
+		k, v, _ := strings.Cut(a, "=")
+		switch k = strings.ToUpper(k); {
+		case textAttrs[k] && typ != "TEXT",
+			k == "NOSTEM" && typ != "TEXT",
+			k == "CASESENSITIVE" && typ != "TAG",
+			k == "WITHSUFFIXTRIE" && typ != "TEXT" && typ != "TAG",
+			k == "SEPARATOR" && typ != "TAG",
+			(k == "FLAT" || k == "HNSW" || vectorAttrs[k]) && typ != "VECTOR":
+			out = append(out, fmt.Sprintf("%s does not apply to %s fields", k, typ))
+		case k == "SOFTDELETE" && typ != "NUMERIC":
+			out = append(out, "SOFTDELETE needs a NUMERIC field (time.Time or unix seconds)")
+		case k == "VERSION" && !intKind(ft):
+			out = append(out, fmt.Sprintf("VERSION field has type %s, want an integer", f.Struct.Type))
+		case (k == "UNIX" || k == "UNIXMS") && ft != timeType:
+			out = append(out, k+" only applies to time.Time fields")
+		case k == "WEIGHT":
+			if w, err := strconv.ParseFloat(v, 64); err != nil || w <= 0 {
+				out = append(out, fmt.Sprintf("WEIGHT=%s is not a positive number", v))
+			}
+		case k == "PHONETIC" && !phoneticMatchers[strings.ToLower(v)]:
+			out = append(out, fmt.Sprintf("PHONETIC=%s is not one of dm:en, dm:fr, dm:pt, dm:es", v))
+		case k == "SEPARATOR" && len(v) != 1:
+			out = append(out, fmt.Sprintf("SEPARATOR=%s must be a single character", v))
+		}
+	}
+	if hasFlag(f.Attrs, "UNIX") && hasFlag(f.Attrs, "UNIXMS") {
+		out = append(out, "conflicting time units UNIX, UNIXMS")
+	}
+	if typ == "VECTOR" {
+		out = append(out, vectorProblems(f)...)
+	}
+	return out
+}
+
+// vectorProblems checks the element type and DIM of a VECTOR field.
+func vectorProblems(f scan.Field) []string {
+	var out []string
+	ft := f.Struct.Type
+	if ft.Kind() != reflect.Array && ft.Kind() != reflect.Slice ||
+		ft.Elem().Kind() != reflect.Float32 && ft.Elem().Kind() != reflect.Float64 {
+		out = append(out, fmt.Sprintf("VECTOR field has type %s, want []float32, [N]float32 or float64 equivalents", ft))
+	}
+	dim := ""
+	for _, a := range f.Attrs {
+		if k, v, ok := strings.Cut(a, "="); ok && strings.EqualFold(k, "DIM") {
+			dim = v
+		}
+	}
+	switch n, err := strconv.Atoi(dim); {
+	case dim == "" && ft.Kind() != reflect.Array:
+		out = append(out, "VECTOR needs DIM=n (or an array type such as [768]float32)")
+	case dim != "" && (err != nil || n <= 0):
+		out = append(out, fmt.Sprintf("DIM=%s is not a positive integer", dim))
+	case dim != "" && ft.Kind() == reflect.Array && n != ft.Len():
+		out = append(out, fmt.Sprintf("DIM=%s does not match array length %d", dim, ft.Len()))
+	}
+	return out
+}
+
+// numericKind reports whether f is stored as something RediSearch can index
+// as NUMERIC.  Strings pass: they may hold numbers written elsewhere.
+func numericKind(f reflect.StructField) bool {
+	if c, ok := scan.CodecForField(f); ok {
+		if _, numeric := c.(scan.NumericCodec); numeric {
+			return true
+		}
+	}
+	ft := f.Type
+	if ft.Kind() == reflect.Pointer {
+		ft = ft.Elem()
+	}
+	switch ft.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64, reflect.Bool, reflect.String:
+		return true
+	}
+	return false
+}
+
+func intKind(t reflect.Type) bool {
+	return t.Kind() >= reflect.Int && t.Kind() <= reflect.Uint64
+}
+
+func hasFlag(attrs []string, want string) bool {
+	for _, a := range attrs {
+		if strings.EqualFold(a, want) {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	// fieldTypes are the tag attributes that pick the SCHEMA field type.
+	fieldTypes = map[string]bool{
+		"TEXT": true, "TAG": true, "NUMERIC": true, "GEO": true, "GEOSHAPE": true, "VECTOR": true,
+	}
+	// tagFlags are the bare attributes BuildSchema, scan and repository read.
+	tagFlags = map[string]bool{
+		"SORTABLE": true, "NOINDEX": true, "NOSTEM": true, "WITHSUFFIXTRIE": true,
+		"CASESENSITIVE": true, "PK": true, "SHADOW": true, "UNIX": true, "UNIXMS": true,
+		"FLAT": true, "HNSW": true, "VERSION": true, "SOFTDELETE": true,
+	}
+	// tagValues are the KEY=VALUE attributes outside vectorAttrs; ENUM, MIN
+	// and MAX are package fake's generation hints.
+	tagValues = map[string]bool{
+		"WEIGHT": true, "PHONETIC": true, "SEPARATOR": true, "REF": true,
+		"ENUM": true, "MIN": true, "MAX": true,
+	}
+
+	phoneticMatchers = map[string]bool{"dm:en": true, "dm:fr": true, "dm:pt": true, "dm:es": true}
+)
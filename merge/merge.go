@@ -0,0 +1,159 @@
+// Package merge holds the scatter-gather helpers used when one logical query
+// is answered by several RediSearch indexes: k-way merging of already-sorted
+// result lists, typed comparison of sort keys, stable re-sorting and
+// first-wins de-duplication.  MultiIndexSearcher is built on it; use it
+// directly when you run your own fan-out.
+//
+//	less := merge.ByKey(func(h scan.Hit) string { return h.Fields["created_ts"] }, true)
+//	hits := merge.KWay([][]scan.Hit{a, b, c}, less)
+//	hits = merge.Dedup(hits, func(h scan.Hit) string { return h.Fields["order_id"] })
+//	hits = merge.Window(hits, 0, 50)
+package merge
+
+import (
+	"container/heap"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// KWay merges lists that are each already ordered by less into one ordered
+// slice.  The merge is stable: on ties the element from the earlier list
+// wins, and elements of the same list keep their relative order.
+func KWay[T any](lists [][]T, less func(a, b T) bool) []T {
+	total := 0
+	h := &cursorHeap[T]{less: less}
+	for i, l := range lists {
+		total += len(l)
+		if len(l) > 0 {
+			h.items = append(h.items, cursor[T]{list: i, items: l})
+		}
+	}
+	heap.Init(h)
+
+	out := make([]T, 0, total)
+	for h.Len() > 0 {
+		c := &h.items[0]
+		out = append(out, c.items[c.pos])
+		c.pos++
+		if c.pos == len(c.items) {
+			heap.Pop(h)
+		} else {
+			heap.Fix(h, 0)
+		}
+	}
+	return out
+}
+
+// Sort returns a stably re-sorted copy of xs; use it when the inputs were
+// not ordered on the merge key (or came back unordered, like FT.AGGREGATE
+// without SORTBY).
+func Sort[T any](xs []T, less func(a, b T) bool) []T {
+	out := slices.Clone(xs)
+	slices.SortStableFunc(out, func(a, b T) int {
+		switch {
+		case less(a, b):
+			return -1
+		case less(b, a):
+			return 1
+		}
+		return 0
+	})
+	return out
+}
+
+// ByKey builds a less function ordering elements by the typed comparison
+// (see Compare) of the string key extracts.  desc flips the order.
+func ByKey[T any](key func(T) string, desc bool) func(a, b T) bool {
+	return func(a, b T) bool {
+		c := Compare(key(a), key(b))
+		if desc {
+			return c > 0
+		}
+		return c < 0
+	}
+}
+
+// Window applies offset/limit to an already merged slice.  A negative limit
+// means "no limit".
+func Window[T any](xs []T, offset, limit int) []T {
+	if offset >= len(xs) {
+		return nil
+	}
+	xs = xs[offset:]
+	if limit >= 0 && limit < len(xs) {
+		xs = xs[:limit]
+	}
+	return xs
+}
+
+// Dedup keeps the first element seen for every key, preserving order.
+func Dedup[T any, K comparable](xs []T, key func(T) K) []T {
+	seen := make(map[K]struct{}, len(xs))
+	out := make([]T, 0, len(xs))
+	for _, x := range xs {
+		k := key(x)
+		if _, ok := seen[k]; ok {
+			continue
+		}
+		seen[k] = struct{}{}
+		out = append(out, x)
+	}
+	return out
+}
+
+// Compare orders two stored sort-key values the way RediSearch would: when
+// both parse as numbers they compare numerically ("9" < "10"), otherwise as
+// strings.  It returns -1, 0 or +1.
+func Compare(a, b string) int {
+	fa, errA := strconv.ParseFloat(a, 64)
+	fb, errB := strconv.ParseFloat(b, 64)
+	if errA == nil && errB == nil {
+		switch {
+		case fa < fb:
+			return -1
+		case fa > fb:
+			return 1
+		}
+		return 0
+	}
+	return strings.Compare(a, b)
+}
+
+// ---------------------------------------------------------------------
+// heap plumbing
+// ---------------------------------------------------------------------
+
+type cursor[T any] struct {
+	list  int // position of the source list, used as tie-break
+	items []T
+	pos   int
+}
+
+type cursorHeap[T any] struct {
+	items []cursor[T]
+	less  func(a, b T) bool
+}
+
+func (h *cursorHeap[T]) Len() int { return len(h.items) }
+
+func (h *cursorHeap[T]) Less(i, j int) bool {
+	a, b := h.items[i], h.items[j]
+	x, y := a.items[a.pos], b.items[b.pos]
+	if h.less(x, y) {
+		return true
+	}
+	if h.less(y, x) {
+		return false
+	}
+	return a.list < b.list
+}
+
+func (h *cursorHeap[T]) Swap(i, j int) { h.items[i], h.items[j] = h.items[j], h.items[i] }
+func (h *cursorHeap[T]) Push(x any)    { h.items = append(h.items, x.(cursor[T])) }
+func (h *cursorHeap[T]) Pop() any {
+	old := h.items
+	x := old[len(old)-1]
+	h.items = old[:len(old)-1]
+	return x
+}
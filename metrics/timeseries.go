@@ -0,0 +1,259 @@
+// Package metrics exports redisorm telemetry into RedisTimeSeries, so teams
+// already dashboarding from Redis get per-index operation counts and
+// latencies without extra infrastructure.
+//
+//	sink := metrics.NewTimeSeriesSink(conn, metrics.WithInterval(10*time.Second))
+//	go sink.Run(ctx)
+//	repo := repository.New("order_idx", sink.Wrap(conn))
+//
+// Every interval, each (index, operation) pair observed gets one sample in
+//
+//	redisorm:metrics:<index>:<op>:count    requests
+//	redisorm:metrics:<index>:<op>:errors   failed requests
+//	redisorm:metrics:<index>:<op>:avg_ms   mean latency
+//	redisorm:metrics:<index>:<op>:p99_ms   99th percentile latency
+//	redisorm:metrics:<index>:<op>:max_ms   worst latency
+//
+// labelled index=<index> op=<op> metric=<name> for TS.MRANGE filtering.
+package metrics
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/manojoshi/redisorm/driver"
+)
+
+// maxSamples bounds the latencies kept per series between flushes.
+const maxSamples = 4096
+
+// Option configures a TimeSeriesSink.
+type Option func(*TimeSeriesSink)
+
+// WithPrefix changes the key prefix (default "redisorm:metrics").
+func WithPrefix(p string) Option { return func(s *TimeSeriesSink) { s.prefix = p } }
+
+// WithInterval sets how often Run flushes (default 10s).
+func WithInterval(d time.Duration) Option { return func(s *TimeSeriesSink) { s.interval = d } }
+
+// WithRetention sets the retention of newly created series (default: the
+// server's, usually unlimited).
+func WithRetention(d time.Duration) Option { return func(s *TimeSeriesSink) { s.retention = d } }
+
+// TimeSeriesSink aggregates observations in memory and writes them out on
+// Flush.  It is safe for concurrent use.
+type TimeSeriesSink struct {
+	exec      driver.Executor
+	prefix    string
+	interval  time.Duration
+	retention time.Duration
+
+	mu    sync.Mutex
+	stats map[series]*window
+}
+
+type series struct{ index, op string }
+
+type window struct {
+	count, errors int
+	sum, max      time.Duration
+	samples       []time.Duration
+}
+
+// NewTimeSeriesSink writes through exec, which should not itself be wrapped
+// by the sink.
+func NewTimeSeriesSink(exec driver.Executor, opts ...Option) *TimeSeriesSink {
+	s := &TimeSeriesSink{
+		exec:     exec,
+		prefix:   "redisorm:metrics",
+		interval: 10 * time.Second,
+		stats:    map[series]*window{},
+	}
+	for _, o := range opts {
+		o(s)
+	}
+	return s
+}
+
+// Wrap returns an executor that records every FT.* command sent through
+// it.  Other commands pass through unrecorded.  A driver.Pipeliner stays
+// one, so pipelined writes keep their single round trip.
+func (s *TimeSeriesSink) Wrap(exec driver.Executor) driver.Executor {
+	if p, ok := exec.(driver.Pipeliner); ok {
+		return &observedPipe{observed{Executor: exec, sink: s}, p}
+	}
+	return &observed{Executor: exec, sink: s}
+}
+
+// Observe records one operation.  Wrap calls it; use it directly for
+// operations that do not go through an executor.
+func (s *TimeSeriesSink) Observe(index, op string, took time.Duration, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	k := series{index, op}
+	w := s.stats[k]
+	if w == nil {
+		w = &window{}
+		s.stats[k] = w
+	}
+	w.count++
+	if err != nil {
+		w.errors++
+	}
+	w.sum += took
+	w.max = max(w.max, took)
+	if len(w.samples) < maxSamples {
+		w.samples = append(w.samples, took)
+	}
+}
+
+// Flush writes one sample per series for the window since the last flush
+// and resets it.
+func (s *TimeSeriesSink) Flush(ctx context.Context) error {
+	s.mu.Lock()
+	stats := s.stats
+	s.stats = map[series]*window{}
+	s.mu.Unlock()
+	if len(stats) == 0 {
+		return nil
+	}
+
+	now := time.Now().UnixMilli()
+	var cmds [][]interface{}
+	for k, w := range stats {
+		slices.Sort(w.samples)
+		p99 := w.samples[(len(w.samples)-1)*99/100]
+		for _, m := range []struct {
+			name string
+			v    float64
+		}{
+			{"count", float64(w.count)},
+			{"errors", float64(w.errors)},
+			{"avg_ms", ms(w.sum) / float64(w.count)},
+			{"p99_ms", ms(p99)},
+			{"max_ms", ms(w.max)},
+		} {
+			cmds = append(cmds, s.add(k, m.name, now, m.v))
+		}
+	}
+	res, err := driver.DoBatch(ctx, s.exec, cmds)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, r := range res {
+		if e, ok := r.(error); ok {
+			errs = append(errs, e)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Run flushes every interval until ctx is done, then flushes once more.
+// Flush errors are dropped: telemetry must never take the caller down.
+func (s *TimeSeriesSink) Run(ctx context.Context) {
+	t := time.NewTicker(s.interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			_ = s.Flush(context.WithoutCancel(ctx))
+			return
+		case <-t.C:
+			_ = s.Flush(ctx)
+		}
+	}
+}
+
+func (s *TimeSeriesSink) add(k series, metric string, ts int64, v float64) []interface{} {
+	key := strings.Join([]string{s.prefix, k.index, k.op, metric}, ":")
+	args := []interface{}{"TS.ADD", key, ts, v}
+	if s.retention > 0 {
+		args = append(args, "RETENTION", s.retention.Milliseconds())
+	}
+	return append(args, "ON_DUPLICATE", "LAST",
+		"LABELS", "index", k.index, "op", k.op, "metric", metric)
+}
+
+func ms(d time.Duration) float64 { return float64(d) / float64(time.Millisecond) }
+
+// observed is the executor returned by Wrap.
+type observed struct {
+	driver.Executor
+	sink *TimeSeriesSink
+}
+
+func (o *observed) Do(ctx context.Context, args ...interface{}) (any, error) {
+	start := time.Now()
+	res, err := o.Executor.Do(ctx, args...)
+	if index, op, ok := operation(args); ok {
+		o.sink.Observe(index, op, time.Since(start), err)
+	}
+	return res, err
+}
+
+// observedPipe is observed over a Pipeliner.  Each FT.* command of a
+// pipeline is recorded with the time the whole round trip took.
+type observedPipe struct {
+	observed
+	pipe driver.Pipeliner
+}
+
+func (o *observedPipe) Pipeline(ctx context.Context, cmds [][]interface{}) ([]any, error) {
+	start := time.Now()
+	res, err := o.pipe.Pipeline(ctx, cmds)
+	took := time.Since(start)
+	for i, cmd := range cmds {
+		index, op, ok := operation(cmd)
+		if !ok {
+			continue
+		}
+		cerr := err
+		if i < len(res) {
+			if rerr, isErr := res[i].(error); isErr {
+				cerr = rerr
+			}
+		}
+		o.sink.Observe(index, op, took, cerr)
+	}
+	return res, err
+}
+
+// operation maps "FT.SEARCH idx …" to ("idx", "search") and "FT.CURSOR
+// READ idx …" to ("idx", "cursor read").  Verbs that name no index
+// (FT.CONFIG, FT._LIST) are recorded under an empty one.
+func operation(args []interface{}) (index, op string, ok bool) {
+	if len(args) == 0 {
+		return "", "", false
+	}
+	verb, _ := args[0].(string)
+	verb = strings.ToUpper(verb)
+	if !strings.HasPrefix(verb, "FT.") {
+		return "", "", false
+	}
+	op = strings.ToLower(strings.TrimPrefix(verb, "FT."))
+	switch verb {
+	case "FT.CONFIG", "FT._LIST":
+		return "", op, true
+	case "FT.CURSOR":
+		if len(args) < 3 {
+			return "", "", false
+		}
+		sub, _ := args[1].(string)
+		index, _ = args[2].(string)
+		op += " " + strings.ToLower(sub)
+	default:
+		if len(args) < 2 {
+			return "", "", false
+		}
+		index, _ = args[1].(string)
+	}
+	if index == "" {
+		return "", "", false
+	}
+	return index, op, true
+}
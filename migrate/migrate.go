@@ -0,0 +1,221 @@
+// Package migrate applies versioned index/data changes and records them in a
+// ledger stored in Redis itself, so every deploy converges on the same state.
+//
+//	m := migrate.New(conn,
+//	    migrate.Migration{
+//	        Version: 2025070601,
+//	        Name:    "orders: add promise_ts",
+//	        Up: func(ctx context.Context, ex driver.Executor) error {
+//	            _, err := ex.Do(ctx, "FT.ALTER", "order_idx", "SCHEMA", "ADD", "promise_ts", "NUMERIC", "SORTABLE")
+//	            return err
+//	        },
+//	        Down: func(ctx context.Context, ex driver.Executor) error { … },
+//	    },
+//	)
+//	applied, err := m.Up(ctx)
+//	…
+//	reverted, err := m.Rollback(ctx, 1) // undo the last one
+//
+// The ledger is a sorted set (version as score and member).  Each ledger
+// update is a single ZADD / ZREM, atomic on any executor.
+package migrate
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/manojoshi/redisorm/driver"
+)
+
+// DefaultLedgerKey is where applied versions are recorded.
+const DefaultLedgerKey = "redisorm:migrations"
+
+var (
+	// ErrNoDown is returned by Rollback when a migration to revert has no Down.
+	ErrNoDown = errors.New("migrate: migration has no Down function")
+	// ErrLocked means another migrator holds the ledger lock.
+	ErrLocked = errors.New("migrate: ledger is locked by another migrator")
+)
+
+// Func performs one migration step.
+type Func func(ctx context.Context, exec driver.Executor) error
+
+// Migration is a single versioned change.  Versions must be unique; they are
+// applied in ascending and reverted in descending order.
+type Migration struct {
+	Version int64
+	Name    string
+	Up      Func
+	Down    Func // optional; required only for Rollback
+}
+
+// Migrator runs migrations against one ledger.
+type Migrator struct {
+	exec       driver.Executor
+	key        string
+	lockTTL    time.Duration
+	migrations []Migration
+}
+
+// New sorts migrations by version and binds them to exec.
+func New(exec driver.Executor, migrations ...Migration) *Migrator {
+	ms := append([]Migration(nil), migrations...)
+	sort.Slice(ms, func(i, j int) bool { return ms[i].Version < ms[j].Version })
+	return &Migrator{exec: exec, key: DefaultLedgerKey, lockTTL: time.Minute, migrations: ms}
+}
+
+// WithLedgerKey stores the ledger under key (one ledger per service/dataset).
+func (m *Migrator) WithLedgerKey(key string) *Migrator { m.key = key; return m }
+
+// WithLockTTL bounds how long a crashed migrator can block others.
+func (m *Migrator) WithLockTTL(d time.Duration) *Migrator { m.lockTTL = d; return m }
+
+// Applied returns the recorded versions in ascending order.
+func (m *Migrator) Applied(ctx context.Context) ([]int64, error) {
+	raw, err := m.exec.Do(ctx, "ZRANGE", m.key, 0, -1)
+	if err != nil {
+		return nil, err
+	}
+	items, _ := raw.([]interface{})
+	out := make([]int64, 0, len(items))
+	for _, it := range items {
+		v, err := strconv.ParseInt(fmt.Sprint(it), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("migrate: corrupt ledger entry %v", it)
+		}
+		out = append(out, v)
+	}
+	return out, nil
+}
+
+// Up applies every pending migration in order and returns the versions it
+// applied.  It stops at the first failure; earlier steps stay recorded.
+func (m *Migrator) Up(ctx context.Context) ([]int64, error) {
+	var done []int64
+	err := m.locked(ctx, func() error {
+		applied, err := m.appliedSet(ctx)
+		if err != nil {
+			return err
+		}
+		for _, mig := range m.migrations {
+			if applied[mig.Version] {
+				continue
+			}
+			if err := mig.Up(ctx, m.exec); err != nil {
+				return fmt.Errorf("migrate: up %d (%s): %w", mig.Version, mig.Name, err)
+			}
+			if err := m.record(ctx, "ZADD", mig.Version); err != nil {
+				return err
+			}
+			done = append(done, mig.Version)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// Rollback reverts the last steps applied migrations, newest first, and
+// returns the versions it reverted.  Every migration to revert must be known
+// to this Migrator and have a Down; this is checked before anything runs.
+func (m *Migrator) Rollback(ctx context.Context, steps int) ([]int64, error) {
+	var done []int64
+	err := m.locked(ctx, func() error {
+		applied, err := m.Applied(ctx)
+		if err != nil {
+			return err
+		}
+		if steps > len(applied) {
+			steps = len(applied)
+		}
+		byVersion := make(map[int64]Migration, len(m.migrations))
+		for _, mig := range m.migrations {
+			byVersion[mig.Version] = mig
+		}
+
+		plan := make([]Migration, 0, steps)
+		for i := len(applied) - 1; i >= len(applied)-steps; i-- {
+			mig, ok := byVersion[applied[i]]
+			if !ok {
+				return fmt.Errorf("migrate: applied version %d is unknown to this migrator", applied[i])
+			}
+			if mig.Down == nil {
+				return fmt.Errorf("%w: %d (%s)", ErrNoDown, mig.Version, mig.Name)
+			}
+			plan = append(plan, mig)
+		}
+
+		for _, mig := range plan {
+			if err := mig.Down(ctx, m.exec); err != nil {
+				return fmt.Errorf("migrate: down %d (%s): %w", mig.Version, mig.Name, err)
+			}
+			if err := m.record(ctx, "ZREM", mig.Version); err != nil {
+				return err
+			}
+			done = append(done, mig.Version)
+		}
+		return nil
+	})
+	return done, err
+}
+
+func (m *Migrator) appliedSet(ctx context.Context) (map[int64]bool, error) {
+	vs, err := m.Applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+	set := make(map[int64]bool, len(vs))
+	for _, v := range vs {
+		set[v] = true
+	}
+	return set, nil
+}
+
+// record adds (ZADD) or removes (ZREM) version.  One command is atomic by
+// itself; wrapping it in MULTI/EXEC through a pooled executor could not
+// make it more so, and could leave a connection inside MULTI.
+func (m *Migrator) record(ctx context.Context, op string, version int64) error {
+	cmd := []interface{}{op, m.key}
+	if op == "ZADD" {
+		cmd = append(cmd, version)
+	}
+	cmd = append(cmd, strconv.FormatInt(version, 10))
+
+	if _, err := m.exec.Do(ctx, cmd...); err != nil {
+		return fmt.Errorf("migrate: ledger %s %d: %w", op, version, driver.Wrap(cmd, err))
+	}
+	return nil
+}
+
+// locked runs fn while holding a best-effort SET NX lock on the ledger.
+func (m *Migrator) locked(ctx context.Context, fn func() error) error {
+	lockKey := m.key + ":lock"
+	token := newToken()
+	res, err := m.exec.Do(ctx, "SET", lockKey, token, "NX", "PX", m.lockTTL.Milliseconds())
+	if errors.Is(err, redis.Nil) || (err == nil && res == nil) {
+		return ErrLocked
+	}
+	if err != nil {
+		return err
+	}
+	defer func() {
+		// compare-and-delete so we never drop someone else's lock
+		_, _ = m.exec.Do(context.WithoutCancel(ctx), "EVAL",
+			`if redis.call("GET", KEYS[1]) == ARGV[1] then return redis.call("DEL", KEYS[1]) end return 0`,
+			1, lockKey, token)
+	}()
+	return fn()
+}
+
+func newToken() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
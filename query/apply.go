@@ -0,0 +1,136 @@
+package query
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Fn is a typed APPLY expression, built by the helpers below and used with
+// AggregateBuilder.Apply or as a computed group key via ByFn.  Invalid
+// arguments are caught here and reported by RawArgs.
+//
+//	q.NewAggregate("order_idx").
+//	    Apply(q.Upper("status"), "status_uc").
+//	    GroupBy(q.ByFn(q.TimeFmt("created_ts", "%Y-%m"), "month"))
+type Fn struct {
+	expr   string
+	fields []string // properties read, LOADed before the APPLY
+	err    error
+}
+
+// String returns the expression as sent to RediSearch.
+func (f Fn) String() string { return f.expr }
+
+// call renders name(@field, args…).
+func call(name, f string, args ...string) Fn {
+	f = field(f)
+	return Fn{
+		expr:   name + "(" + strings.Join(append([]string{f}, args...), ", ") + ")",
+		fields: []string{f},
+	}
+}
+
+func bad(name, format string, args ...any) Fn {
+	return Fn{err: fmt.Errorf("%w: %s: %s", ErrSyntax, name, fmt.Sprintf(format, args...))}
+}
+
+// TimeFmt formats a unix-seconds field with a strftime layout ("%Y-%m-%d").
+func TimeFmt(f, layout string) Fn {
+	if !strings.Contains(layout, "%") {
+		return bad("timefmt", "layout %q has no %% directive", layout)
+	}
+	return call("timefmt", f, strconv.Quote(layout))
+}
+
+// ParseTime parses a string field with a strptime layout into unix seconds.
+func ParseTime(f, layout string) Fn {
+	if !strings.Contains(layout, "%") {
+		return bad("parsetime", "layout %q has no %% directive", layout)
+	}
+	return call("parsetime", f, strconv.Quote(layout))
+}
+
+// MonthOfYear is the month (0–11) of a unix-seconds field.
+func MonthOfYear(f string) Fn { return call("monthofyear", f) }
+
+// Upper and Lower change the case of a string field.
+func Upper(f string) Fn { return call("upper", f) }
+func Lower(f string) Fn { return call("lower", f) }
+
+// Substr takes count bytes of a string field from offset; a negative
+// offset is counted from the end of the string, and a count of -1 takes
+// the rest of it: Substr("sku", -4, -1) is the last four bytes.
+func Substr(f string, offset, count int) Fn {
+	return call("substr", f, strconv.Itoa(offset), strconv.Itoa(count))
+}
+
+// Format is printf-style formatting with %s verbs only, one per field:
+// Format("%s-%s", "warehouse_id", "status").
+func Format(format string, fields ...string) Fn {
+	verbs := strings.Count(format, "%s")
+	if strings.Count(format, "%")-2*strings.Count(format, "%%") != verbs {
+		return bad("format", "only %%s verbs are supported in %q", format)
+	}
+	if verbs != len(fields) {
+		return bad("format", "%q has %d verbs for %d fields", format, verbs, len(fields))
+	}
+	fn := Fn{expr: "format(" + strconv.Quote(format)}
+	for _, f := range fields {
+		f = field(f)
+		fn.expr += ", " + f
+		fn.fields = append(fn.fields, f)
+	}
+	fn.expr += ")"
+	return fn
+}
+
+var propRe = regexp.MustCompile(`@[A-Za-z_][A-Za-z0-9_]*`)
+
+// RawFn is an APPLY expression passed through verbatim, for functions the
+// helpers don't cover: RawFn("day(@created_ts) + 3600").  The @properties
+// it mentions are LOADed.
+func RawFn(expr string) Fn {
+	var fs []string
+	for _, p := range propRe.FindAllString(expr, -1) {
+		if !slices.Contains(fs, p) {
+			fs = append(fs, p)
+		}
+	}
+	return Fn{expr: expr, fields: fs}
+}
+
+// ByFn groups by a computed expression, exposed as column alias.
+func ByFn(fn Fn, alias string) GroupKey {
+	return GroupKey{apply: fn.expr, alias: alias, load: fn.fields, err: fn.err}
+}
+
+// Apply adds an APPLY stage computing fn into alias.  Stages keep their
+// place relative to GroupBy: one added before it runs on the documents and
+// can feed the group keys and reducers, one added after it runs on the
+// grouped rows and can read the reducer aliases:
+//
+//	b.GroupBy(q.By("sku")).Reduce("SUM", "qty", "total").Reduce("COUNT", "", "n").
+//	    ApplyExpr("@total / @n", "avg_qty")
+func (b *AggregateBuilder) Apply(fn Fn, alias string) *AggregateBuilder {
+	if fn.err != nil {
+		if b.err == nil {
+			b.err = fn.err
+		}
+		return b
+	}
+	g := GroupKey{apply: fn.expr, alias: alias, load: fn.fields}
+	if b.grouped {
+		b.postApplies = append(b.postApplies, g)
+		return b
+	}
+	b.applies = append(b.applies, g)
+	return b
+}
+
+// ApplyExpr is Apply with a verbatim expression, see RawFn.
+func (b *AggregateBuilder) ApplyExpr(expr, alias string) *AggregateBuilder {
+	return b.Apply(RawFn(expr), alias)
+}
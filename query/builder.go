@@ -2,10 +2,13 @@ package query
 
 import (
 	"context"
-	"errors"
+	"fmt"
 	"github.com/manojoshi/redisorm/scan"
+	"maps"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/manojoshi/redisorm/driver"
 )
@@ -24,11 +27,26 @@ const (
 type SearchBuilder struct {
 	idx           string
 	where         Expr
+	also          []Expr // AndWhere conditions, kept across Where
 	returnFields  []string
 	sortField     string
 	dir           Dir
 	offset, limit int
 	withTotal     bool
+	withScores    bool
+	noContent     bool
+	minScore      float64
+	hasMinScore   bool
+	dedupField    string
+	inKeys        []string
+	inFields      []string
+	after         *Cursor
+	params        map[string]any             // PARAMS, implies DIALECT 2
+	summarize     []interface{}              // SUMMARIZE …, nil when off
+	highlight     []interface{}              // HIGHLIGHT …, nil when off
+	scorer        string                     // SCORER, "" for the server default
+	schema        *schema                    // Schema: fields must exist in the model
+	codecs        map[string]scan.FieldCodec // Model: time fields' codecs for range bounds
 	executor      driver.Executor
 }
 
@@ -38,19 +56,128 @@ func NewSearch(index string) *SearchBuilder {
 }
 
 func (b *SearchBuilder) Where(e Expr) *SearchBuilder { b.where = e; return b }
+
+// AndWhere adds a condition every hit must also match.  Unlike Where it
+// accumulates and survives a later Where, so options can narrow a query
+// whatever its main clause.
+func (b *SearchBuilder) AndWhere(e Expr) *SearchBuilder {
+	b.also = append(b.also, e)
+	return b
+}
+
 func (b *SearchBuilder) Select(fs ...string) *SearchBuilder {
 	b.returnFields = append([]string{}, fs...)
 	return b
 }
+
+// Highlight wraps the query terms found in fields (all TEXT fields when
+// none are given) in openTag and closeTag, "<b>" and "</b>" when both are
+// empty.  The marked-up text replaces the field value in the hits.
+func (b *SearchBuilder) Highlight(fields []string, openTag, closeTag string) *SearchBuilder {
+	b.highlight = append([]interface{}{"HIGHLIGHT"}, fieldsClause(fields)...)
+	if openTag != "" || closeTag != "" {
+		b.highlight = append(b.highlight, "TAGS", openTag, closeTag)
+	}
+	return b
+}
+
+// Summarize replaces the value of fields (all TEXT fields when none are
+// given) with fragments around the matched terms: up to frags fragments of
+// length words each, joined by sep.  Zero values keep the server defaults
+// (3 fragments of 20 words, "... ").
+func (b *SearchBuilder) Summarize(fields []string, frags, length int, sep string) *SearchBuilder {
+	b.summarize = append([]interface{}{"SUMMARIZE"}, fieldsClause(fields)...)
+	if frags > 0 {
+		b.summarize = append(b.summarize, "FRAGS", strconv.Itoa(frags))
+	}
+	if length > 0 {
+		b.summarize = append(b.summarize, "LEN", strconv.Itoa(length))
+	}
+	if sep != "" {
+		b.summarize = append(b.summarize, "SEPARATOR", sep)
+	}
+	return b
+}
+
+// fieldsClause is the optional "FIELDS n f …" of HIGHLIGHT / SUMMARIZE.
+func fieldsClause(fields []string) []interface{} {
+	if len(fields) == 0 {
+		return nil
+	}
+	out := []interface{}{"FIELDS", strconv.Itoa(len(fields))}
+	for _, f := range fields {
+		out = append(out, f)
+	}
+	return out
+}
+
 func (b *SearchBuilder) SortBy(f string, d Dir) *SearchBuilder {
 	b.sortField, b.dir = f, d
 	return b
 }
+
+// Sorting returns the SortBy field and direction ("" when unsorted).
+func (b *SearchBuilder) Sorting() (field string, dir Dir) { return b.sortField, b.dir }
 func (b *SearchBuilder) Limit(off, lim int) *SearchBuilder {
 	b.offset, b.limit = off, lim
 	return b
 }
 func (b *SearchBuilder) WithTotal() *SearchBuilder { b.withTotal = true; return b }
+
+// WithScores asks RediSearch to return each document's relevance score.
+// Decode such replies with scan.DecodeHits(raw, true).
+func (b *SearchBuilder) WithScores() *SearchBuilder { b.withScores = true; return b }
+
+// NoContent returns document keys only (NOCONTENT): no field payloads are
+// read or sent, which is far cheaper when the caller only needs IDs for an
+// MGET or a cache lookup.  Decoded hits have empty Fields; see Keys.
+func (b *SearchBuilder) NoContent() *SearchBuilder { b.noContent = true; return b }
+
+// Scorer ranks full-text hits with the named scoring function: one of the
+// Scorer* constants or an extension registered on the server.
+func (b *SearchBuilder) Scorer(name string) *SearchBuilder { b.scorer = name; return b }
+
+// Built-in RediSearch scoring functions for Scorer.  The server default is
+// TFIDF, or BM25STD on recent releases.
+const (
+	ScorerTFIDF        = "TFIDF"
+	ScorerTFIDFDocNorm = "TFIDF.DOCNORM" // TFIDF normalised by document length
+	ScorerBM25         = "BM25"
+	ScorerBM25Std      = "BM25STD"
+	ScorerDisMax       = "DISMAX"   // sum of the matched term frequencies
+	ScorerDocScore     = "DOCSCORE" // the document's own score, see index.WithScoreField
+	ScorerHamming      = "HAMMING"  // Hamming distance of binary payloads
+)
+
+// MinScore drops hits whose relevance score is below min.  RediSearch has no
+// server-side cut-off for FT.SEARCH, so this implies WithScores and filters
+// while decoding; LIMIT is still applied by the server first, so a page may
+// come back shorter than requested.
+func (b *SearchBuilder) MinScore(min float64) *SearchBuilder {
+	b.withScores, b.minScore, b.hasMinScore = true, min, true
+	return b
+}
+
+// Params binds the values of Param placeholders (PARAMS … DIALECT 2).  It
+// may be called repeatedly; later values win.
+func (b *SearchBuilder) Params(kv map[string]any) *SearchBuilder {
+	b.params = mergeParams(b.params, kv)
+	return b
+}
+
+// InKeys restricts the search to the given document keys (INKEYS).
+func (b *SearchBuilder) InKeys(keys ...string) *SearchBuilder {
+	b.inKeys = append([]string{}, keys...)
+	return b
+}
+
+// InFields limits full-text matching to the given TEXT fields (INFIELDS);
+// field-scoped clauses such as Match are unaffected.
+func (b *SearchBuilder) InFields(fields ...string) *SearchBuilder {
+	b.inFields = append([]string{}, fields...)
+	return b
+}
+
 func (b *SearchBuilder) Using(ex driver.Executor) *SearchBuilder {
 	b.executor = ex
 	return b
@@ -58,15 +185,53 @@ func (b *SearchBuilder) Using(ex driver.Executor) *SearchBuilder {
 
 // RawArgs gives you the complete arg slice for logging / pipeline use.
 func (b *SearchBuilder) RawArgs() ([]interface{}, error) {
+	where, offset := b.afterArgs(conj(b.where, b.also...))
+	where = encodeBounds(foldKNN(where), b.codecs)
+	if err := b.checkSchema(where); err != nil {
+		return nil, err
+	}
+	params, sortField, dir := b.params, b.sortField, b.dir
 	var q string
-	if b.where == nil || b.where == MatchAll() {
+	switch w := where.(type) {
+	case nil, matchAll:
 		q = "*"
-	} else {
-		q = "(" + Compile(b.where) + ")"
+	case raw:
+		q = string(w)
+	case *knn:
+		q = Compile(w) // "…=>[KNN …]" must not be parenthesised
+		if sortField == "" {
+			sortField, dir = w.alias, Asc
+		}
+	default:
+		if hasKNN(where) {
+			return nil, fmt.Errorf("%w: KNN cannot be nested under Or or Not", ErrSyntax)
+		}
+		q = "(" + Compile(where) + ")"
 	}
 
 	args := []interface{}{"FT.SEARCH", b.idx, q}
 
+	if b.noContent {
+		args = append(args, "NOCONTENT")
+	}
+	if b.withScores {
+		args = append(args, "WITHSCORES")
+	}
+
+	if len(b.inKeys) > 0 {
+		args = append(args, "INKEYS", strconv.Itoa(len(b.inKeys)))
+		for _, k := range b.inKeys {
+			args = append(args, k)
+		}
+	}
+
+	if len(b.inFields) > 0 {
+		args = append(args, "INFIELDS", strconv.Itoa(len(b.inFields)))
+		for _, f := range b.inFields {
+			args = append(args, f)
+		}
+	}
+
 	if len(b.returnFields) > 0 {
 		args = append(args, "RETURN", strconv.Itoa(len(b.returnFields)))
 		for _, f := range b.returnFields {
@@ -74,32 +239,192 @@ func (b *SearchBuilder) RawArgs() ([]interface{}, error) {
 		}
 	}
 
-	if b.sortField != "" {
-		args = append(args, "SORTBY", b.sortField, string(b.dir))
+	args = append(args, b.summarize...)
+	args = append(args, b.highlight...)
+	if b.scorer != "" {
+		args = append(args, "SCORER", b.scorer)
+	}
+
+	if sortField != "" {
+		args = append(args, "SORTBY", sortField, string(dir))
 	}
 
 	// LIMIT
-	args = append(args, "LIMIT", strconv.Itoa(b.offset), strconv.Itoa(b.limit))
+	args = append(args, "LIMIT", strconv.Itoa(offset), strconv.Itoa(b.limit))
 
-	return args, nil
+	return appendParams(args, params, where), nil
+}
+
+// conj ANDs xs onto where, treating nil and MatchAll as no condition.
+func conj(where Expr, xs ...Expr) Expr {
+	if len(xs) == 0 {
+		return where
+	}
+	if where == nil || where == MatchAll() {
+		if len(xs) == 1 {
+			return xs[0]
+		}
+		return And(xs...)
+	}
+	return And(append([]Expr{where}, xs...)...)
+}
+
+// appendParams emits PARAMS n k v … DIALECT d, values in their stored form,
+// adding the params where's nodes bind.  d is 2, or higher when where's
+// syntax needs it; without params DIALECT is only set when needed.
+func appendParams(args []interface{}, params map[string]any, where Expr) []interface{} {
+	d := 0
+	if where != nil {
+		params = mergeParams(params, nodeParams(where))
+		d = dialect(where)
+	}
+	if len(params) == 0 {
+		if d > 0 {
+			args = append(args, "DIALECT", strconv.Itoa(d))
+		}
+		return args
+	}
+	names := slices.Sorted(maps.Keys(params))
+	args = append(args, "PARAMS", strconv.Itoa(2*len(names)))
+	for _, n := range names {
+		if blob, ok := params[n].([]byte); ok {
+			args = append(args, n, blob)
+			continue
+		}
+		args = append(args, n, value(params[n]))
+	}
+	return append(args, "DIALECT", strconv.Itoa(max(d, 2)))
 }
 
 // Run executes the command and decodes into []T (struct or map).
 func (b *SearchBuilder) Run(ctx context.Context) ([]map[string]string, error) {
+	var rows []map[string]string
+	err := b.run(ctx, func(raw any) (err error) {
+		rows, err = b.Decode(raw)
+		return err
+	})
+	return rows, err
+}
+
+// RunResult is Run keeping document keys, scores and the total number of
+// matches (Result.Total), which is independent of LIMIT.
+func (b *SearchBuilder) RunResult(ctx context.Context) (scan.Result, error) {
+	var res scan.Result
+	err := b.run(ctx, func(raw any) (err error) {
+		if res.Total, err = scan.Total(raw); err != nil {
+			return err
+		}
+		res.Hits, err = b.DecodeHits(raw)
+		return err
+	})
+	return res, err
+}
+
+// Keys runs the search with NoContent and returns the matching keys.
+func (b *SearchBuilder) Keys(ctx context.Context) ([]string, error) {
+	b.NoContent()
+	var keys []string
+	err := b.run(ctx, func(raw any) error {
+		hits, err := b.DecodeHits(raw)
+		keys = make([]string, len(hits))
+		for i, h := range hits {
+			keys[i] = h.Key
+		}
+		return err
+	})
+	return keys, err
+}
+
+// run sends the command and hands the reply to decode.
+func (b *SearchBuilder) run(ctx context.Context, decode func(raw any) error) error {
 	if b.executor == nil {
-		return nil, errors.New("query: executor not set (call Using())")
+		return ErrNoExecutor
 	}
 	args, err := b.RawArgs()
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	raw, err := b.executor.Do(ctx, args...)
+	if err != nil {
+		return driver.Wrap(args, err)
+	}
+
+	start := time.Now()
+	defer func() { driver.LogDecode(ctx, b.executor, args, time.Since(start)) }()
+	err = decode(raw)
+	if err == nil && b.where != nil && expands(b.where) {
+		err = partial(raw)
+	}
+	return err
+}
+
+// partial reports a RESP-3 expansion-limit warning as ErrPartialResults.
+// RESP-2 replies carry no warnings; use Lint with CheckExpansion there.
+func partial(raw any) error {
+	for _, w := range scan.Warnings(raw) {
+		if strings.Contains(strings.ToLower(w), "expansion") {
+			return fmt.Errorf("%w: %s", ErrPartialResults, w)
+		}
+	}
+	return nil
+}
+
+// Dedup keeps only the first hit for every distinct value of field, after
+// sorting — SortBy("created_ts", Desc).Dedup("customer_id") yields the latest
+// document per customer.  Hits lacking the field are always kept.  Like
+// MinScore it runs client-side on the returned page.
+func (b *SearchBuilder) Dedup(field string) *SearchBuilder {
+	b.dedupField = strings.TrimPrefix(field, "@")
+	return b
+}
+
+// Decode turns a reply to this builder's RawArgs into maps, honouring
+// WithScores / MinScore.  Use it when the command was sent elsewhere
+// (pipelines, custom executors).
+func (b *SearchBuilder) Decode(raw any) ([]map[string]string, error) {
+	if !b.withScores && !b.noContent && b.dedupField == "" {
+		return scan.DecodeMaps(raw)
+	}
+	hits, err := b.DecodeHits(raw)
 	if err != nil {
 		return nil, err
 	}
+	out := make([]map[string]string, len(hits))
+	for i, h := range hits {
+		out[i] = h.Fields
+	}
+	return out, nil
+}
 
-	return scan.DecodeMaps(raw)
+// DecodeHits is Decode keeping document keys and scores.
+func (b *SearchBuilder) DecodeHits(raw any) ([]scan.Hit, error) {
+	decode := scan.DecodeHits
+	if b.noContent {
+		decode = scan.DecodeKeyHits
+	}
+	hits, err := decode(raw, b.withScores)
+	if err != nil || (!b.hasMinScore && b.dedupField == "") {
+		return hits, err
+	}
+	var seen map[string]struct{}
+	if b.dedupField != "" {
+		seen = make(map[string]struct{}, len(hits))
+	}
+	kept := hits[:0]
+	for _, h := range hits {
+		if b.hasMinScore && h.Score < b.minScore {
+			continue
+		}
+		if v, ok := h.Fields[b.dedupField]; ok && seen != nil {
+			if _, dup := seen[v]; dup {
+				continue
+			}
+			seen[v] = struct{}{}
+		}
+		kept = append(kept, h)
+	}
+	return kept, nil
 }
 
 // -------------------------------------------------------------------
@@ -109,29 +434,134 @@ func (b *SearchBuilder) Run(ctx context.Context) ([]map[string]string, error) {
 type AggregateBuilder struct {
 	idx           string
 	where         Expr
+	also          []Expr
 	groups        []GroupKey
+	applies       []GroupKey // APPLY stages before GROUPBY
+	postApplies   []GroupKey // APPLY stages added after GroupBy, run after the reducers
+	loads         []string   // Load: extra LOAD properties
+	grouped       bool       // GroupBy was called
 	reducers      []reducer
+	filters       []string
+	sorts         []SortKey
+	sortMax       int
+	params        map[string]any
 	offset, limit int
+	limitSet      bool
+	cursorCount   int                        // WITHCURSOR COUNT, 0 = off
+	schema        *schema                    // Schema: fields must exist in the model
+	codecs        map[string]scan.FieldCodec // Model: time fields' codecs for range bounds
 	executor      driver.Executor
+	err           error // first build error, reported by RawArgs
 }
 
-type reducer struct{ fn, field, alias string }
+type reducer struct {
+	fn, alias string
+	args      []string // after nargs: "@qty", "0.99", "BY", …
+}
 
 func NewAggregate(index string) *AggregateBuilder {
 	return &AggregateBuilder{idx: index, limit: 10_000}
 }
 
 func (b *AggregateBuilder) Where(e Expr) *AggregateBuilder { b.where = e; return b }
+
+// AndWhere adds a condition, as on SearchBuilder.
+func (b *AggregateBuilder) AndWhere(e Expr) *AggregateBuilder {
+	b.also = append(b.also, e)
+	return b
+}
+
 func (b *AggregateBuilder) GroupBy(keys ...GroupKey) *AggregateBuilder {
-	b.groups = keys
+	b.groups, b.grouped = keys, true
+	return b
+}
+func (b *AggregateBuilder) Reduce(fn, f, as string) *AggregateBuilder {
+	if strings.EqualFold(fn, "COUNT") || f == "" {
+		return b.ReduceArgs(fn, as)
+	}
+	return b.ReduceArgs(fn, as, field(f))
+}
+
+// ReduceArgs adds a reducer taking any number of arguments, written after
+// the nargs count RediSearch expects:
+// ReduceArgs("QUANTILE", "p99", "@qty", "0.99").
+func (b *AggregateBuilder) ReduceArgs(fn, as string, args ...string) *AggregateBuilder {
+	b.reducers = append(b.reducers, reducer{fn, as, args})
+	return b
+}
+
+// Filter appends a FILTER step evaluated after the reducers, e.g.
+// "@avg_qty > 3".  The expression is passed through verbatim.
+func (b *AggregateBuilder) Filter(expr string) *AggregateBuilder {
+	b.filters = append(b.filters, expr)
+	return b
+}
+
+// SortKey is one SORTBY property of an aggregate.
+type SortKey struct {
+	Field string
+	Dir   Dir
+}
+
+// SortAsc and SortDesc build SortKeys; the field may be a reducer alias.
+func SortAsc(field string) SortKey  { return SortKey{field, Asc} }
+func SortDesc(field string) SortKey { return SortKey{field, Desc} }
+
+// SortBy orders the rows after grouping and FILTER.  Keys accumulate across
+// calls, earlier ones taking precedence.
+func (b *AggregateBuilder) SortBy(keys ...SortKey) *AggregateBuilder {
+	b.sorts = append(b.sorts, keys...)
+	return b
+}
+
+// SortMax keeps only the top n rows of SORTBY (MAX n), letting RediSearch
+// sort a heap of n rather than the whole result.
+func (b *AggregateBuilder) SortMax(n int) *AggregateBuilder { b.sortMax = n; return b }
+
+// Having is the typed form of Filter: Having("orders", ">", 10) emits
+// FILTER "@orders > 10".  Strings are quoted; op must be one of
+// == != > >= < <=.
+func (b *AggregateBuilder) Having(name, op string, v any) *AggregateBuilder {
+	switch op {
+	case "==", "!=", ">", ">=", "<", "<=":
+	default:
+		if b.err == nil {
+			b.err = fmt.Errorf("%w: unsupported Having operator %q", ErrSyntax, op)
+		}
+		return b
+	}
+	val := toStr(v)
+	if s, ok := v.(string); ok {
+		val = strconv.Quote(s)
+	}
+	return b.Filter(field(name) + " " + op + " " + val)
+}
+
+// Params binds Param placeholders, as on SearchBuilder.
+func (b *AggregateBuilder) Params(kv map[string]any) *AggregateBuilder {
+	b.params = mergeParams(b.params, kv)
 	return b
 }
-func (b *AggregateBuilder) Reduce(fn, field, as string) *AggregateBuilder {
-	b.reducers = append(b.reducers, reducer{fn, field, as})
+
+// Load adds properties to the LOAD clause, for rows that need document
+// fields (or "@__key") without grouping: Load("@__key").
+func (b *AggregateBuilder) Load(fields ...string) *AggregateBuilder {
+	for _, f := range fields {
+		b.loads = append(b.loads, field(f))
+	}
 	return b
 }
+
 func (b *AggregateBuilder) Limit(off, lim int) *AggregateBuilder {
-	b.offset, b.limit = off, lim
+	b.offset, b.limit, b.limitSet = off, lim, true
+	return b
+}
+
+// WithCursor reads the result through a cursor, count rows per round trip
+// (WITHCURSOR COUNT n); consume it with Stream.  The default LIMIT is then
+// dropped so the whole result streams – an explicit Limit still applies.
+func (b *AggregateBuilder) WithCursor(count int) *AggregateBuilder {
+	b.cursorCount = count
 	return b
 }
 func (b *AggregateBuilder) Using(ex driver.Executor) *AggregateBuilder {
@@ -140,36 +570,118 @@ func (b *AggregateBuilder) Using(ex driver.Executor) *AggregateBuilder {
 }
 
 func (b *AggregateBuilder) RawArgs() ([]interface{}, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	where := encodeBounds(conj(b.where, b.also...), b.codecs)
+	if err := b.checkSchema(where); err != nil {
+		return nil, err
+	}
 	var q string
-	if b.where == nil || b.where == MatchAll() {
+	switch w := where.(type) {
+	case nil, matchAll:
 		q = "*"
-	} else {
-		q = "(" + Compile(b.where) + ")"
+	case raw:
+		q = string(w)
+	default:
+		q = "(" + Compile(where) + ")"
 	}
 
 	args := []interface{}{"FT.AGGREGATE", b.idx, q}
 
-	args = append(args, "GROUPBY", strconv.Itoa(len(b.groups)))
-	for _, g := range b.groups {
-		args = append(args, g.raw)
+	// APPLY stages and computed group keys: LOAD their source fields,
+	// APPLY, then group on the alias
+	computed := append(slices.Clip(b.applies), b.groups...)
+	var loads []interface{}
+	for _, f := range b.loads {
+		if !slices.Contains(loads, interface{}(f)) {
+			loads = append(loads, f)
+		}
+	}
+	computedAs := map[string]bool{}
+	for _, g := range computed {
+		if g.err != nil {
+			return nil, g.err
+		}
+		for _, f := range g.load {
+			if !computedAs[f] && !slices.Contains(loads, interface{}(f)) {
+				loads = append(loads, f)
+			}
+		}
+		if g.apply != "" {
+			computedAs["@"+g.alias] = true // an earlier APPLY's output, not a document field
+		}
+	}
+	if len(loads) > 0 {
+		args = append(args, "LOAD", strconv.Itoa(len(loads)))
+		args = append(args, loads...)
+	}
+	for _, g := range computed {
+		if g.apply != "" {
+			args = append(args, "APPLY", g.apply, "AS", g.alias)
+		}
+	}
+
+	if b.grouped || len(b.reducers) > 0 { // a bare Load streams the documents ungrouped
+		args = append(args, "GROUPBY", strconv.Itoa(len(b.groups)))
+		for _, g := range b.groups {
+			args = append(args, g.key())
+		}
 	}
 
 	for _, r := range b.reducers {
-		if strings.EqualFold(r.fn, "COUNT") {
-			args = append(args, "REDUCE", r.fn, "0", "AS", r.alias)
-			continue
+		args = append(args, "REDUCE", r.fn, strconv.Itoa(len(r.args)))
+		for _, a := range r.args {
+			args = append(args, a)
+		}
+		args = append(args, "AS", r.alias)
+	}
+
+	for _, g := range b.postApplies {
+		args = append(args, "APPLY", g.apply, "AS", g.alias)
+	}
+
+	for _, f := range b.filters {
+		args = append(args, "FILTER", f)
+	}
+
+	if len(b.sorts) > 0 {
+		args = append(args, "SORTBY", strconv.Itoa(2*len(b.sorts)))
+		for _, k := range b.sorts {
+			dir := k.Dir
+			if dir == "" {
+				dir = Asc
+			}
+			args = append(args, field(k.Field), string(dir))
+		}
+		if b.sortMax > 0 {
+			args = append(args, "MAX", strconv.Itoa(b.sortMax))
 		}
-		args = append(args, "REDUCE", r.fn, "1", "@"+r.field, "AS", r.alias)
 	}
 
-	args = append(args, "LIMIT", strconv.Itoa(b.offset), strconv.Itoa(b.limit))
+	if b.cursorCount == 0 || b.limitSet {
+		args = append(args, "LIMIT", strconv.Itoa(b.offset), strconv.Itoa(b.limit))
+	}
+	if b.cursorCount > 0 {
+		args = append(args, "WITHCURSOR", "COUNT", strconv.Itoa(b.cursorCount))
+	}
 
-	return args, nil
+	return appendParams(args, b.params, where), nil
 }
 
 func (b *AggregateBuilder) Run(ctx context.Context) ([]map[string]string, error) {
 	if b.executor == nil {
-		return nil, errors.New("query: executor not set (call Using())")
+		return nil, ErrNoExecutor
+	}
+	if b.cursorCount > 0 { // drain the cursor
+		var out []map[string]string
+		for row, err := range b.Stream(ctx) {
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, row)
+		}
+		return out, nil
 	}
 	args, err := b.RawArgs()
 	if err != nil {
@@ -178,7 +690,10 @@ func (b *AggregateBuilder) Run(ctx context.Context) ([]map[string]string, error)
 
 	raw, err := b.executor.Do(ctx, args...)
 	if err != nil {
-		return nil, err
+		return nil, driver.Wrap(args, err)
 	}
+
+	start := time.Now()
+	defer func() { driver.LogDecode(ctx, b.executor, args, time.Since(start)) }()
 	return scan.DecodeMaps(raw)
 }
@@ -2,8 +2,12 @@ package query
 
 import (
 	"fmt"
+	"reflect"
+	"slices"
 	"strconv"
 	"strings"
+
+	"github.com/manojoshi/redisorm/scan"
 )
 
 // Compile turns an Expr tree into a RediSearch query string.
@@ -21,7 +25,7 @@ func Compile(e Expr) string {
 // -------------------------------------------------------------------
 
 func (n *eq) compile(sb *strings.Builder) {
-	fmt.Fprintf(sb, "%s:{%v}", field(n.f), n.v)
+	fmt.Fprintf(sb, "%s:{%s}", field(n.f), tagValue(n.v))
 }
 
 func (n *in) compile(sb *strings.Builder) {
@@ -30,17 +34,123 @@ func (n *in) compile(sb *strings.Builder) {
 		if i > 0 {
 			sb.WriteByte('|')
 		}
-		fmt.Fprint(sb, v)
+		sb.WriteString(tagValue(v))
 	}
 	sb.WriteByte('}')
 }
 
+// tagValue renders a TAG value with RediSearch's special characters
+// escaped; RawValue and Param pass through.
+func tagValue(v any) string {
+	switch v := v.(type) {
+	case RawValue:
+		return string(v)
+	case Param:
+		return value(v)
+	}
+	return escapeTerm(value(v))
+}
+
 func (n *rng) compile(sb *strings.Builder) {
-	left, right := "(", ")"
-	if n.inc {
-		left, right = "[", "]"
+	fmt.Fprintf(sb, "%s:[%s %s]", field(n.f), bound(n.lo, n.loOpen), bound(n.hi, n.hiOpen))
+}
+
+// bound renders one end of a numeric range; infinities are never exclusive.
+func bound(v any, open bool) string {
+	s := value(v)
+	if !open || s == "-inf" || s == "+inf" || s == "inf" {
+		return s
+	}
+	return "(" + s
+}
+
+func (n *pfx) compile(sb *strings.Builder) {
+	fmt.Fprintf(sb, "%s:%s*", field(n.f), escapeTerm(n.p))
+}
+
+func (n *fuzzy) compile(sb *strings.Builder) {
+	pct := strings.Repeat("%", n.dist)
+	fmt.Fprintf(sb, "%s:%s%s%s", field(n.f), pct, escapeTerm(n.term), pct)
+}
+
+func (n *wildcard) compile(sb *strings.Builder) {
+	fmt.Fprintf(sb, "%s:w'%s'", field(n.f), strings.ReplaceAll(n.pattern, "'", `\'`))
+}
+
+// escapeTerm backslash-escapes the characters RediSearch would read as
+// query syntax or token separators, so a term is matched literally.
+func escapeTerm(s string) string {
+	var sb strings.Builder
+	for _, r := range s {
+		if strings.ContainsRune(",.<>{}[]\"':;!@#$%^&*()-+=~|/\\ ", r) {
+			sb.WriteByte('\\')
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+// expands reports whether e contains nodes RediSearch expands into term
+// lists, which MAXPREFIXEXPANSIONS may truncate.
+func expands(e Expr) bool {
+	switch n := e.(type) {
+	case *pfx, *fuzzy, *wildcard:
+		return true
+	case *and:
+		return slices.ContainsFunc(n.xs, expands)
+	case *or:
+		return slices.ContainsFunc(n.xs, expands)
+	case *not:
+		return expands(n.x)
+	}
+	return false
+}
+
+// dialect is the lowest DIALECT e's syntax needs, 0 for the default.
+func dialect(e Expr) int {
+	d := 0
+	walk(e, func(x Expr) {
+		switch x.(type) {
+		case *wildcard, *knn:
+			d = max(d, 2)
+		case *geoBox:
+			d = max(d, 3)
+		}
+	})
+	return d
+}
+
+// nodeParams collects the PARAMS that nodes such as KNN and GeoBox bind
+// themselves.
+func nodeParams(e Expr) map[string]any {
+	var out map[string]any
+	walk(e, func(x Expr) {
+		if p, ok := x.(interface{ params() map[string]any }); ok {
+			out = mergeParams(out, p.params())
+		}
+	})
+	return out
+}
+
+// walk calls fn for e and every node below it.
+func walk(e Expr, fn func(Expr)) {
+	fn(e)
+	switch n := e.(type) {
+	case *and:
+		for _, x := range n.xs {
+			walk(x, fn)
+		}
+	case *or:
+		for _, x := range n.xs {
+			walk(x, fn)
+		}
+	case *not:
+		walk(n.x, fn)
+	case *knn:
+		if n.filter != nil {
+			walk(n.filter, fn)
+		}
 	}
-	fmt.Fprintf(sb, "%s:%s%v %v%s", field(n.f), left, n.lo, n.hi, right)
 }
 
 func (n *and) compile(sb *strings.Builder) { group(sb, n.xs, " ") }
@@ -65,6 +175,24 @@ func group(sb *strings.Builder, xs []Expr, sep string) {
 	sb.WriteByte(')')
 }
 
+// value renders a query operand.  Types with a scan.FieldCodec (registered
+// enums, big numbers, …) compile to their stored form so filters match what
+// the repository wrote.  time.Time compiles to unix seconds here; builders
+// bound with Model first rewrite range bounds with the field's own codec.
+func value(v any) string {
+	if p, ok := v.(Param); ok {
+		return "$" + string(p)
+	}
+	if v != nil {
+		if c, ok := scan.CodecFor(reflect.TypeOf(v)); ok {
+			if s, err := c.Encode(reflect.ValueOf(v)); err == nil {
+				return s
+			}
+		}
+	}
+	return fmt.Sprint(v)
+}
+
 // -------------------------------------------------------------------
 // Small utility: convert any int-like to string *without* reflection.
 // -------------------------------------------------------------------
@@ -0,0 +1,135 @@
+package query
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidToken is returned by Tokens.Decode for tampered, truncated or
+// foreign page tokens.
+var ErrInvalidToken = errors.New("query: invalid page token")
+
+// Cursor is the pagination state carried between pages.  With a NUMERIC
+// sort field it uses search-after semantics – the next page starts at Last,
+// skipping the Ties documents already returned with that value – so pages
+// stay stable under concurrent inserts.  Otherwise it falls back to Offset.
+type Cursor struct {
+	Field  string `json:"f,omitempty"`
+	Dir    Dir    `json:"d,omitempty"`
+	Last   string `json:"l,omitempty"`
+	Ties   int    `json:"t,omitempty"`
+	Offset int    `json:"o,omitempty"`
+	Key    string `json:"k,omitempty"` // last document returned, when known
+}
+
+// Tokens turns Cursors into signed, opaque strings for REST APIs so clients
+// can neither see nor forge raw offsets.
+type Tokens struct{ secret []byte }
+
+// NewTokens signs with HMAC-SHA256 under secret.
+func NewTokens(secret []byte) *Tokens { return &Tokens{secret: secret} }
+
+// Encode returns the token for c.
+func (t *Tokens) Encode(c Cursor) string {
+	body, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(body) + "." +
+		base64.RawURLEncoding.EncodeToString(t.sign(body))
+}
+
+// Decode verifies token and returns its Cursor.  The empty token is the
+// first page.
+func (t *Tokens) Decode(token string) (Cursor, error) {
+	var c Cursor
+	if token == "" {
+		return c, nil
+	}
+	b64, sig64, ok := strings.Cut(token, ".")
+	if !ok {
+		return c, ErrInvalidToken
+	}
+	body, err1 := base64.RawURLEncoding.DecodeString(b64)
+	sig, err2 := base64.RawURLEncoding.DecodeString(sig64)
+	if err1 != nil || err2 != nil || !hmac.Equal(sig, t.sign(body)) {
+		return c, ErrInvalidToken
+	}
+	if err := json.Unmarshal(body, &c); err != nil {
+		return c, ErrInvalidToken
+	}
+	return c, nil
+}
+
+func (t *Tokens) sign(body []byte) []byte {
+	m := hmac.New(sha256.New, t.secret)
+	m.Write(body)
+	return m.Sum(nil)
+}
+
+// After continues from a cursor returned by Next.  The cursor's sort order
+// replaces the builder's, so a page sequence cannot switch order midway.
+func (b *SearchBuilder) After(c Cursor) *SearchBuilder {
+	b.after = &c
+	if c.Field != "" {
+		b.sortField, b.dir = c.Field, c.Dir
+	}
+	return b
+}
+
+// Next returns the cursor of the page after rows, which must be the decoded
+// result of this builder; ok is false once the last page was returned.
+// The sort field must be among the returned fields for search-after.
+func (b *SearchBuilder) Next(rows []map[string]string) (c Cursor, ok bool) {
+	if len(rows) == 0 || len(rows) < b.limit {
+		return Cursor{}, false
+	}
+	c = Cursor{Field: b.sortField, Dir: b.dir}
+	prev := Cursor{}
+	if b.after != nil {
+		prev = *b.after
+	}
+
+	f := strings.TrimPrefix(b.sortField, "@")
+	last, found := rows[len(rows)-1][f]
+	if _, err := strconv.ParseFloat(last, 64); b.sortField == "" || !found || err != nil {
+		if prev.Last != "" { // search-after page whose rows lack the field
+			c.Last, c.Ties = prev.Last, prev.Ties+len(rows)
+			return c, true
+		}
+		c.Offset = b.offset + len(rows)
+		if b.after != nil {
+			c.Offset = prev.Offset + len(rows) // the offset this page was read at
+		}
+		return c, true
+	}
+
+	c.Last = last
+	for i := len(rows) - 1; i >= 0 && rows[i][f] == last; i-- {
+		c.Ties++
+	}
+	if c.Ties == len(rows) && prev.Last == last { // the whole page was one tie run
+		c.Ties += prev.Ties
+	}
+	return c, true
+}
+
+// afterArgs applies the cursor to the query and offset.
+func (b *SearchBuilder) afterArgs(where Expr) (Expr, int) {
+	c := b.after
+	if c == nil {
+		return where, b.offset
+	}
+	if c.Last == "" {
+		return where, c.Offset
+	}
+	var bound Expr
+	if c.Dir == Desc {
+		bound = Lte(c.Field, c.Last)
+	} else {
+		bound = Gte(c.Field, c.Last)
+	}
+	return conj(where, bound), c.Ties
+}
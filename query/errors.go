@@ -0,0 +1,22 @@
+package query
+
+import (
+	"errors"
+
+	"github.com/manojoshi/redisorm/driver"
+)
+
+// ErrNoExecutor is returned by Run when Using() was never called.
+var ErrNoExecutor = errors.New("query: executor not set (call Using())")
+
+// ErrPartialResults is returned by Run, together with the rows, when the
+// server stopped expanding a prefix / fuzzy term at MAXPREFIXEXPANSIONS and
+// the result may be missing matches.
+var ErrPartialResults = errors.New("query: partial results")
+
+// Re-exported from driver; executor errors returned by Run are classified.
+var (
+	ErrSyntax        = driver.ErrSyntax
+	ErrIndexNotFound = driver.ErrIndexNotFound
+	ErrTimeout       = driver.ErrTimeout
+)
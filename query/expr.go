@@ -34,11 +34,51 @@ func Eq(field string, v any) Expr { return &eq{field, v} }
 // In("@field", v1, v2) ➜ "@field:{v1|v2}"
 func In(field string, vs ...any) Expr { return &in{field, vs} }
 
-// Range("@price", "[10 100]")  ➜ "@price:[10 100]"
+// Range("@price", 10, 100, true)  ➜ "@price:[10 100]"
+// Range("@price", 10, 100, false) ➜ "@price:[(10 (100]"
 func Range(field string, min, max any, inclusive bool) Expr {
-	return &rng{field, min, max, inclusive}
+	return &rng{field, min, max, !inclusive, !inclusive}
 }
 
+// Gt("qty", 5)  ➜ "@qty:[(5 +inf]"
+func Gt(field string, v any) Expr { return &rng{field, v, "+inf", true, false} }
+
+// Gte("qty", 5) ➜ "@qty:[5 +inf]"
+func Gte(field string, v any) Expr { return &rng{field, v, "+inf", false, false} }
+
+// Lt("qty", 5)  ➜ "@qty:[-inf (5]"
+func Lt(field string, v any) Expr { return &rng{field, "-inf", v, false, true} }
+
+// Lte("qty", 5) ➜ "@qty:[-inf 5]"
+func Lte(field string, v any) Expr { return &rng{field, "-inf", v, false, false} }
+
+// Between("qty", 5, 10) ➜ "@qty:[5 10]"   (both ends inclusive)
+func Between(field string, lo, hi any) Expr { return &rng{field, lo, hi, false, false} }
+
+// Prefix("title", "lap*")  ➜ "@title:lap*"   (TEXT fields; the * is optional)
+func Prefix(field string, prefix string) Expr {
+	return &pfx{field, strings.TrimSuffix(prefix, "*")}
+}
+
+// Wildcard("sku", "ab?-*9")  ➜ "@sku:w'ab?-*9'"   (? one char, * any; DIALECT 2)
+func Wildcard(field string, pattern string) Expr { return &wildcard{field, pattern} }
+
+// Fuzzy("title", "laptop", 1)  ➜ "@title:%laptop%"   (distance 1–3)
+func Fuzzy(field string, term string, distance int) Expr {
+	return &fuzzy{field, term, min(max(distance, 1), 3)}
+}
+
+// Param is a query parameter placeholder usable wherever a value is:
+// Eq("status", q.Param("st")) ➜ "@status:{$st}".  Bind it with
+// SearchBuilder.Params; the value then travels in PARAMS, unescaped and
+// without being spliced into the query text.
+type Param string
+
+// RawValue is a TAG value written to the query as is, for callers that
+// escape themselves: Eq("sku", q.RawValue(`AB\-12`)).  Other Eq / In
+// values get "-", "|", "@", spaces etc. backslash-escaped.
+type RawValue string
+
 // ------------
 // Combinators
 // ------------
@@ -61,9 +101,19 @@ type (
 		vs []any
 	}
 	rng struct {
-		f      string
-		lo, hi any
-		inc    bool
+		f              string
+		lo, hi         any
+		loOpen, hiOpen bool // exclusive bound: "(" prefix
+	}
+	pfx struct {
+		f, p string
+	}
+	fuzzy struct {
+		f, term string
+		dist    int
+	}
+	wildcard struct {
+		f, pattern string
 	}
 	and struct{ xs []Expr }
 	or  struct{ xs []Expr }
@@ -0,0 +1,64 @@
+package query
+
+// Typed field handles, one type per RediSearch field type, so a query can
+// only use the operators its field supports.  redisorm-gen emits them per
+// model from the redisorm tags:
+//
+//	//go:generate go run github.com/manojoshi/redisorm/cmd/redisorm-gen -type Order
+//
+//	q.And(OrderFields.Status.Eq("PENDING"), OrderFields.Qty.Between(1, 5))
+//
+// A renamed or removed field then breaks the build instead of silently
+// matching nothing.  The handles are strings holding the field name, for
+// the options that take one: repository.SortAsc(OrderFields.Qty.Name()).
+type (
+	TagField      string // TAG
+	NumericField  string // NUMERIC, time.Time, SHADOW siblings
+	TextField     string // TEXT
+	GeoField      string // GEO
+	GeoShapeField string // GEOSHAPE
+	VectorField   string // VECTOR
+)
+
+func (f TagField) Name() string                   { return string(f) }
+func (f TagField) Eq(v any) Expr                  { return Eq(string(f), v) }
+func (f TagField) In(vs ...any) Expr              { return In(string(f), vs...) }
+func (f TagField) Wildcard(pattern string) Expr   { return Wildcard(string(f), pattern) }
+func (f TagField) EqIf(v any) Expr                { return EqIf(string(f), v) }
+func (f NumericField) Name() string               { return string(f) }
+func (f NumericField) Gt(v any) Expr              { return Gt(string(f), v) }
+func (f NumericField) Gte(v any) Expr             { return Gte(string(f), v) }
+func (f NumericField) Lt(v any) Expr              { return Lt(string(f), v) }
+func (f NumericField) Lte(v any) Expr             { return Lte(string(f), v) }
+func (f NumericField) Between(lo, hi any) Expr    { return Between(string(f), lo, hi) }
+func (f TextField) Name() string                  { return string(f) }
+func (f TextField) Match(terms string) Expr       { return Match(string(f), terms) }
+func (f TextField) Prefix(prefix string) Expr     { return Prefix(string(f), prefix) }
+func (f TextField) Wildcard(pattern string) Expr  { return Wildcard(string(f), pattern) }
+func (f TextField) Fuzzy(term string, d int) Expr { return Fuzzy(string(f), term, d) }
+func (f GeoField) Name() string                   { return string(f) }
+func (f GeoShapeField) Name() string              { return string(f) }
+func (f VectorField) Name() string                { return string(f) }
+
+// Range is Range on the field: both ends inclusive or both exclusive.
+func (f NumericField) Range(lo, hi any, inclusive bool) Expr {
+	return Range(string(f), lo, hi, inclusive)
+}
+
+// Eq matches documents whose value is exactly v: "@qty:[v v]".
+func (f NumericField) Eq(v any) Expr { return Between(string(f), v, v) }
+
+// Radius is GeoRadius on the field.
+func (f GeoField) Radius(lon, lat, radius float64, unit GeoUnit) Expr {
+	return GeoRadius(string(f), lon, lat, radius, unit)
+}
+
+// Within is GeoBox on the field.
+func (f GeoShapeField) Within(minLon, minLat, maxLon, maxLat float64) Expr {
+	return GeoBox(string(f), minLon, minLat, maxLon, maxLat)
+}
+
+// KNN is KNN on the field.
+func (f VectorField) KNN(k int, vector []float32, opts ...KNNOpt) Expr {
+	return KNN(string(f), k, vector, opts...)
+}
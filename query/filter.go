@@ -0,0 +1,113 @@
+package query
+
+import "reflect"
+
+// Helpers for filters assembled from optional request parameters, where
+// most conditions only apply when the caller supplied a value:
+//
+//	where := q.AndSkipNil(
+//	    q.EqIf("status", req.Status),
+//	    q.Maybe(req.MinQty > 0, q.Gte("qty", req.MinQty)),
+//	    q.Maybe(req.Query != "", q.Match("title", req.Query)),
+//	)
+
+// Maybe returns e when cond holds and nil otherwise, for AndSkipNil,
+// OrSkipNil and FilterSet to drop.
+func Maybe(cond bool, e Expr) Expr {
+	if !cond {
+		return nil
+	}
+	return e
+}
+
+// EqIf is Eq unless v is nil or its type's zero value ("", 0, a nil
+// pointer), in which case it returns nil.  A non-nil pointer is
+// dereferenced, so *string request fields tell "absent" from "empty".
+func EqIf(field string, v any) Expr {
+	rv := reflect.ValueOf(v)
+	if !rv.IsValid() || rv.IsZero() {
+		return nil
+	}
+	if rv.Kind() == reflect.Pointer {
+		v = rv.Elem().Interface()
+	}
+	return Eq(field, v)
+}
+
+// AndSkipNil is And over the non-nil xs.  A single remaining condition is
+// returned as is, none at all gives MatchAll.
+func AndSkipNil(xs ...Expr) Expr {
+	kept := skipNil(xs)
+	switch len(kept) {
+	case 0:
+		return MatchAll()
+	case 1:
+		return kept[0]
+	}
+	return And(kept...)
+}
+
+// OrSkipNil is Or over the non-nil xs, with AndSkipNil's special cases.
+// A MatchAll operand matches everything, and so does the result.
+func OrSkipNil(xs ...Expr) Expr {
+	for _, x := range xs {
+		if x == MatchAll() {
+			return x
+		}
+	}
+	kept := skipNil(xs)
+	switch len(kept) {
+	case 0:
+		return MatchAll()
+	case 1:
+		return kept[0]
+	}
+	return Or(kept...)
+}
+
+// skipNil drops nil operands and MatchAll, the identity of And.
+func skipNil(xs []Expr) []Expr {
+	kept := make([]Expr, 0, len(xs))
+	for _, x := range xs {
+		if x != nil && x != MatchAll() {
+			kept = append(kept, x)
+		}
+	}
+	return kept
+}
+
+// FilterSet accumulates the conditions of a dynamic filter; the zero value
+// is empty and ready to use.  Nil conditions are ignored.
+//
+//	var f q.FilterSet
+//	f.AddIf(req.Status != "", q.Eq("status", req.Status))
+//	if req.From != nil {
+//	    f.Add(q.Gte("created_ts", req.From.Unix()))
+//	}
+//	docs, err := repo.Search(ctx, f.Expr())
+type FilterSet struct{ xs []Expr }
+
+// Add appends the non-nil conditions xs.  MatchAll is kept, for Expr and
+// Any to combine: it drops out of the AND but makes the OR match everything.
+func (f *FilterSet) Add(xs ...Expr) *FilterSet {
+	for _, x := range xs {
+		if x != nil {
+			f.xs = append(f.xs, x)
+		}
+	}
+	return f
+}
+
+// AddIf appends e when cond holds.
+func (f *FilterSet) AddIf(cond bool, e Expr) *FilterSet {
+	return f.Add(Maybe(cond, e))
+}
+
+// Len returns the number of conditions added.
+func (f *FilterSet) Len() int { return len(f.xs) }
+
+// Expr ANDs the conditions; an empty set matches everything.
+func (f *FilterSet) Expr() Expr { return AndSkipNil(f.xs...) }
+
+// Any ORs the conditions; an empty set matches everything.
+func (f *FilterSet) Any() Expr { return OrSkipNil(f.xs...) }
@@ -0,0 +1,71 @@
+package query
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// GeoUnit is the radius unit of GeoRadius.
+type GeoUnit string
+
+const (
+	M  GeoUnit = "m"
+	KM GeoUnit = "km"
+	MI GeoUnit = "mi"
+	FT GeoUnit = "ft"
+)
+
+// GeoRadius matches GEO fields within radius of lon/lat:
+//
+//	GeoRadius("location", 77.59, 12.97, 5, q.KM)  ➜ "@location:[77.59 12.97 5 km]"
+func GeoRadius(field string, lon, lat, radius float64, unit GeoUnit) Expr {
+	return &geoRadius{field, lon, lat, radius, unit}
+}
+
+// GeoBox matches GEOSHAPE fields lying within the lon/lat rectangle:
+//
+//	GeoBox("area", 77.5, 12.9, 77.7, 13.1)  ➜ "@area:[WITHIN $__area_box]"
+//
+// RediSearch has no box query for plain GEO fields, so the field must be
+// tagged GEOSHAPE.  The polygon travels as a PARAMS value and the builder
+// switches to DIALECT 3.
+func GeoBox(field string, minLon, minLat, maxLon, maxLat float64) Expr {
+	return &geoBox{field, minLon, minLat, maxLon, maxLat}
+}
+
+type (
+	geoRadius struct {
+		f             string
+		lon, lat, rad float64
+		unit          GeoUnit
+	}
+	geoBox struct {
+		f                              string
+		minLon, minLat, maxLon, maxLat float64
+	}
+)
+
+func (n *geoRadius) compile(sb *strings.Builder) {
+	fmt.Fprintf(sb, "%s:[%s %s %s %s]", field(n.f), coord(n.lon), coord(n.lat), coord(n.rad), n.unit)
+}
+
+func (n *geoBox) compile(sb *strings.Builder) {
+	fmt.Fprintf(sb, "%s:[WITHIN $%s]", field(n.f), n.param())
+}
+
+// param is the PARAMS name carrying the polygon.
+func (n *geoBox) param() string { return "__" + strings.TrimPrefix(n.f, "@") + "_box" }
+
+// params returns the rectangle as a closed WKT polygon.
+func (n *geoBox) params() map[string]any {
+	lo, la := []float64{n.minLon, n.maxLon, n.maxLon, n.minLon, n.minLon},
+		[]float64{n.minLat, n.minLat, n.maxLat, n.maxLat, n.minLat}
+	pts := make([]string, len(lo))
+	for i := range lo {
+		pts[i] = coord(lo[i]) + " " + coord(la[i])
+	}
+	return map[string]any{n.param(): "POLYGON((" + strings.Join(pts, ", ") + "))"}
+}
+
+func coord(f float64) string { return strconv.FormatFloat(f, 'f', -1, 64) }
@@ -5,6 +5,9 @@ import "strings"
 type GroupKey struct {
 	raw   string
 	alias string
+	apply string   // computed key: APPLY apply AS alias, then GROUPBY @alias
+	load  []string // fields the APPLY reads, loaded first
+	err   error    // invalid Fn passed to ByFn
 }
 
 func By(field string) GroupKey {
@@ -17,3 +20,26 @@ func By(field string) GroupKey {
 func ByExpr(expr string) GroupKey { return GroupKey{raw: expr} }
 
 func (g GroupKey) As(alias string) GroupKey { g.alias = alias; return g }
+
+// GroupByHour buckets a unix-seconds field by hour: the key is the bucket's
+// start, in the column "<field>_hour" unless renamed with As.
+func GroupByHour(field string) GroupKey { return timeBucket(field, "hour") }
+
+// GroupByDay buckets a unix-seconds field by UTC day ("<field>_day").
+func GroupByDay(field string) GroupKey { return timeBucket(field, "day") }
+
+// GroupByMonth buckets a unix-seconds field by UTC month ("<field>_month").
+func GroupByMonth(field string) GroupKey { return timeBucket(field, "month") }
+
+// timeBucket groups by fn(@field), RediSearch's rounding date functions.
+func timeBucket(f, fn string) GroupKey {
+	return ByFn(call(fn, f), strings.TrimPrefix(f, "@")+"_"+fn)
+}
+
+// key is the GROUPBY property.
+func (g GroupKey) key() string {
+	if g.apply != "" {
+		return "@" + g.alias
+	}
+	return g.raw
+}
@@ -0,0 +1,153 @@
+package query
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+)
+
+// KNN parameter names; don't reuse them in Params.
+const (
+	KNNParamK   = "k"
+	KNNParamVec = "vec"
+)
+
+// KNNOpt tunes a KNN query.
+type KNNOpt func(*knn)
+
+// KNNFilter restricts the candidates to documents matching e (hybrid
+// query); the default is every document.
+func KNNFilter(e Expr) KNNOpt { return func(n *knn) { n.filter = e } }
+
+// ScoreAs names the distance column (default "__<field>_score").
+func ScoreAs(alias string) KNNOpt { return func(n *knn) { n.alias = alias } }
+
+// EFRuntime sets the HNSW EF_RUNTIME for this query.
+func EFRuntime(ef int) KNNOpt { return func(n *knn) { n.efRuntime = ef } }
+
+// KNN finds the k documents whose vector field is closest to vector:
+//
+//	q.KNN("embedding", 10, vec, q.KNNFilter(q.Eq("status", "PENDING")))
+//	➜ (@status:{PENDING})=>[KNN $k @embedding $vec AS __embedding_score]
+//
+// The vector travels as a PARAMS blob and the builder switches to DIALECT 2.
+// Results are sorted by distance unless SortBy says otherwise.  Conditions
+// ANDed with KNN (q.And, AndWhere) are folded into its KNNFilter; KNN cannot
+// be nested under Or or Not.
+func KNN(field string, k int, vector []float32, opts ...KNNOpt) Expr {
+	n := &knn{f: field, k: k, vec: vector}
+	for _, o := range opts {
+		o(n)
+	}
+	if n.alias == "" {
+		n.alias = "__" + strings.TrimPrefix(field, "@") + "_score"
+	}
+	return n
+}
+
+type knn struct {
+	f         string
+	k         int
+	vec       []float32
+	filter    Expr
+	alias     string
+	efRuntime int
+}
+
+func (n *knn) compile(sb *strings.Builder) {
+	if n.filter == nil || n.filter == MatchAll() {
+		sb.WriteString("(*)")
+	} else {
+		sb.WriteByte('(')
+		n.filter.compile(sb)
+		sb.WriteByte(')')
+	}
+	fmt.Fprintf(sb, "=>[KNN $%s %s $%s", KNNParamK, field(n.f), KNNParamVec)
+	if n.efRuntime > 0 {
+		fmt.Fprintf(sb, " EF_RUNTIME %d", n.efRuntime)
+	}
+	fmt.Fprintf(sb, " AS %s]", n.alias)
+}
+
+// params returns the PARAMS the node needs.
+func (n *knn) params() map[string]any {
+	return map[string]any{KNNParamK: n.k, KNNParamVec: VectorBlob(n.vec)}
+}
+
+// VectorBlob encodes a FLOAT32 vector the way RediSearch stores and expects
+// it: little-endian IEEE-754, 4 bytes per element.
+func VectorBlob(v []float32) string {
+	b := make([]byte, 4*len(v))
+	for i, x := range v {
+		binary.LittleEndian.PutUint32(b[4*i:], math.Float32bits(x))
+	}
+	return string(b)
+}
+
+// mergeParams returns base plus extra without modifying either.
+func mergeParams(base, extra map[string]any) map[string]any {
+	out := make(map[string]any, len(base)+len(extra))
+	for k, v := range base {
+		out[k] = v
+	}
+	for k, v := range extra {
+		out[k] = v
+	}
+	return out
+}
+
+// foldKNN moves the conditions ANDed with a KNN node into its prefilter,
+// so And(KNN(…), cond) – from AndWhere, a cursor, a repository scope or
+// query middleware – runs as the hybrid query it means.  Other shapes are
+// returned as they are.
+func foldKNN(e Expr) Expr {
+	a, ok := e.(*and)
+	if !ok {
+		return e
+	}
+	var n *knn
+	var rest []Expr
+	for _, x := range flattenAnd(a) {
+		if k, ok := x.(*knn); ok && n == nil {
+			n = k
+			continue
+		}
+		rest = append(rest, x)
+	}
+	if n == nil {
+		return e
+	}
+	c := *n
+	c.filter = conj(c.filter, rest...)
+	return &c
+}
+
+// flattenAnd lists the operands of a, expanding nested Ands.
+func flattenAnd(a *and) []Expr {
+	var out []Expr
+	for _, x := range a.xs {
+		if inner, ok := x.(*and); ok {
+			out = append(out, flattenAnd(inner)...)
+			continue
+		}
+		out = append(out, x)
+	}
+	return out
+}
+
+// hasKNN reports whether a KNN node is nested somewhere in e.
+func hasKNN(e Expr) bool {
+	switch n := e.(type) {
+	case *knn:
+		return true
+	case *and:
+		return slices.ContainsFunc(n.xs, hasKNN)
+	case *or:
+		return slices.ContainsFunc(n.xs, hasKNN)
+	case *not:
+		return hasKNN(n.x)
+	}
+	return false
+}
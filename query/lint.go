@@ -0,0 +1,183 @@
+package query
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/manojoshi/redisorm/scan"
+)
+
+// Lint codes – stable identifiers suitable for metrics and allow-lists.
+const (
+	LintLeadingWildcard = "leading-wildcard" // "*foo" forces a full term scan
+	LintLargeIn         = "large-in"         // IN list above the configured limit
+	LintEmptyIn         = "empty-in"         // IN with no values never matches
+	LintEmptyRange      = "empty-range"      // lower bound above upper bound
+	LintUnindexedField  = "unindexed-field"  // field missing from / NOINDEX in the bound model
+	LintNotMatchAll     = "not-match-all"    // -(*) never matches
+	LintPrefixExpansion = "prefix-expansion" // prefix/fuzzy term may hit MAXPREFIXEXPANSIONS
+)
+
+// Warning is one finding of Lint.
+type Warning struct {
+	Code    string
+	Field   string // empty for structural findings
+	Message string
+}
+
+func (w Warning) String() string { return w.Code + ": " + w.Message }
+
+// Warnings is the result of Lint.
+type Warnings []Warning
+
+// Err turns the findings into an error for callers who enforce the linter
+// instead of just logging it; nil when there are none.
+func (ws Warnings) Err() error {
+	if len(ws) == 0 {
+		return nil
+	}
+	msgs := make([]string, len(ws))
+	for i, w := range ws {
+		msgs[i] = w.String()
+	}
+	return fmt.Errorf("query: lint: %s", strings.Join(msgs, "; "))
+}
+
+// LintOpt tunes Lint.
+type LintOpt func(*lintCfg)
+
+type lintCfg struct {
+	maxIn     int
+	indexed   map[string]bool // nil: no model bound
+	expansion bool            // report LintPrefixExpansion
+}
+
+// MaxIn sets the IN list size above which LintLargeIn is reported (default 100).
+func MaxIn(n int) LintOpt { return func(c *lintCfg) { c.maxIn = n } }
+
+// CheckExpansion enables LintPrefixExpansion: prefixes shorter than three
+// characters and fuzzy terms shorter than five (or at distance 2+) are
+// flagged as likely to exceed the server's MAXPREFIXEXPANSIONS and return
+// partial results.  Lint does not query the server, so this is a rule of
+// thumb rather than a count of the terms the index holds.
+func CheckExpansion() LintOpt { return func(c *lintCfg) { c.expansion = true } }
+
+// ForModel binds a struct model so fields it does not index are reported.
+func ForModel(model any) LintOpt {
+	return func(c *lintCfg) { c.indexed = indexedFields(model) }
+}
+
+// Lint walks e looking for patterns known to be slow or to match nothing.
+// It never rejects anything by itself; log the result or call Err on it.
+//
+//	if ws := q.Lint(where, q.ForModel(Order{})); len(ws) > 0 {
+//	    log.Printf("slow query: %v", ws)
+//	}
+func Lint(e Expr, opts ...LintOpt) Warnings {
+	cfg := &lintCfg{maxIn: 100}
+	for _, o := range opts {
+		o(cfg)
+	}
+	var ws Warnings
+	lint(e, cfg, &ws)
+	return ws
+}
+
+func lint(e Expr, cfg *lintCfg, ws *Warnings) {
+	add := func(code, f, format string, args ...any) {
+		*ws = append(*ws, Warning{Code: code, Field: f, Message: fmt.Sprintf(format, args...)})
+	}
+	checkField := func(f string) {
+		name := strings.TrimPrefix(f, "@")
+		if cfg.indexed != nil && !cfg.indexed[name] {
+			add(LintUnindexedField, name, "%s is not indexed by the model", name)
+		}
+	}
+
+	switch n := e.(type) {
+	case *eq:
+		checkField(n.f)
+	case *in:
+		checkField(n.f)
+		switch {
+		case len(n.vs) == 0:
+			add(LintEmptyIn, strings.TrimPrefix(n.f, "@"), "IN on %s has no values and never matches", n.f)
+		case len(n.vs) > cfg.maxIn:
+			add(LintLargeIn, strings.TrimPrefix(n.f, "@"), "IN on %s has %d values (limit %d)", n.f, len(n.vs), cfg.maxIn)
+		}
+	case *rng:
+		checkField(n.f)
+		lo, errLo := strconv.ParseFloat(value(n.lo), 64)
+		hi, errHi := strconv.ParseFloat(value(n.hi), 64)
+		if errLo == nil && errHi == nil && (lo > hi || (lo == hi && (n.loOpen || n.hiOpen))) {
+			add(LintEmptyRange, strings.TrimPrefix(n.f, "@"), "range on %s is empty", n.f)
+		}
+	case *pfx:
+		checkField(n.f)
+		if cfg.expansion && len(n.p) < 3 {
+			add(LintPrefixExpansion, strings.TrimPrefix(n.f, "@"),
+				"prefix %q on %s may expand past MAXPREFIXEXPANSIONS", n.p, n.f)
+		}
+	case *fuzzy:
+		checkField(n.f)
+		if cfg.expansion && (n.dist > 1 || len(n.term) < 5) {
+			add(LintPrefixExpansion, strings.TrimPrefix(n.f, "@"),
+				"fuzzy term %q on %s may expand past MAXPREFIXEXPANSIONS", n.term, n.f)
+		}
+	case *wildcard:
+		checkField(n.f)
+		if strings.HasPrefix(n.pattern, "*") && len(n.pattern) > 1 {
+			add(LintLeadingWildcard, strings.TrimPrefix(n.f, "@"), "leading wildcard %q scans every term", n.pattern)
+		}
+	case *text:
+		if n.f != "" { // Text searches every TEXT field
+			checkField(n.f)
+		}
+	case *knn:
+		checkField(n.f)
+		if n.filter != nil {
+			lint(n.filter, cfg, ws)
+		}
+	case *geoRadius:
+		checkField(n.f)
+	case *geoBox:
+		checkField(n.f)
+	case *and:
+		for _, x := range n.xs {
+			lint(x, cfg, ws)
+		}
+	case *or:
+		for _, x := range n.xs {
+			lint(x, cfg, ws)
+		}
+	case *not:
+		if _, ok := n.x.(matchAll); ok {
+			add(LintNotMatchAll, "", "NOT of MatchAll never matches")
+			return
+		}
+		lint(n.x, cfg, ws)
+	}
+}
+
+// indexedFields lists the attributes model makes searchable: every tagged
+// field except NOINDEX / PK ones (SHADOW fields are searchable as name_num).
+func indexedFields(model any) map[string]bool {
+	out := map[string]bool{}
+	for _, f := range scan.Fields(reflect.TypeOf(model)) {
+		name, indexed := f.Name, !scan.MetaField(f.Name)
+		for _, a := range f.Attrs {
+			switch strings.ToUpper(a) {
+			case "NOINDEX", "PK":
+				indexed = false
+			case "SHADOW":
+				name += "_num"
+			}
+		}
+		if indexed {
+			out[name] = true
+		}
+	}
+	return out
+}
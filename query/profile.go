@@ -0,0 +1,311 @@
+package query
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/manojoshi/redisorm/driver"
+)
+
+// PlanNode is one node of the execution plan FT.EXPLAINCLI prints: an
+// operator (INTERSECT, UNION, NUMERIC {0 <= @qty <= 10}, …) or a leaf term.
+type PlanNode struct {
+	Op       string
+	Children []*PlanNode
+}
+
+// String renders the plan the way FT.EXPLAINCLI prints it.
+func (n *PlanNode) String() string {
+	var sb strings.Builder
+	n.write(&sb, 0)
+	return sb.String()
+}
+
+func (n *PlanNode) write(sb *strings.Builder, depth int) {
+	pad := strings.Repeat("  ", depth)
+	if len(n.Children) == 0 {
+		sb.WriteString(pad + n.Op + "\n")
+		return
+	}
+	sb.WriteString(pad + n.Op + " {\n")
+	for _, c := range n.Children {
+		c.write(sb, depth+1)
+	}
+	sb.WriteString(pad + "}\n")
+}
+
+// Explain asks the server how it will execute the query (FT.EXPLAINCLI)
+// without running it.  Only the query string and its dialect are sent:
+// sorting, paging and the returned fields do not change the plan.
+func (b *SearchBuilder) Explain(ctx context.Context) (*PlanNode, error) {
+	if b.executor == nil {
+		return nil, ErrNoExecutor
+	}
+	args, err := b.RawArgs()
+	if err != nil {
+		return nil, err
+	}
+	return explain(ctx, b.executor, args)
+}
+
+// explain runs FT.EXPLAINCLI for the FT.SEARCH / FT.AGGREGATE args.
+func explain(ctx context.Context, exec driver.Executor, args []interface{}) (*PlanNode, error) {
+	cmd := []interface{}{"FT.EXPLAINCLI", args[1], args[2]}
+	for i := 3; i < len(args)-1; i++ {
+		if args[i] == "DIALECT" {
+			cmd = append(cmd, "DIALECT", args[i+1])
+		}
+	}
+	raw, err := exec.Do(ctx, cmd...)
+	if err != nil {
+		return nil, driver.Wrap(cmd, err)
+	}
+	lines, ok := raw.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("query: unexpected FT.EXPLAINCLI reply %T", raw)
+	}
+	return parsePlan(lines)
+}
+
+// parsePlan builds the tree from EXPLAINCLI's lines, where "OP {" opens a
+// node and "}" closes it.  Several top-level lines get a synthetic root.
+func parsePlan(lines []interface{}) (*PlanNode, error) {
+	root := &PlanNode{}
+	stack := []*PlanNode{root}
+	for _, l := range lines {
+		s := strings.TrimSpace(fmt.Sprint(l))
+		top := stack[len(stack)-1]
+		switch {
+		case s == "":
+		case s == "}":
+			if len(stack) == 1 {
+				return nil, fmt.Errorf("query: unbalanced FT.EXPLAINCLI output")
+			}
+			stack = stack[:len(stack)-1]
+		case strings.HasSuffix(s, "{"):
+			n := &PlanNode{Op: strings.TrimSpace(strings.TrimSuffix(s, "{"))}
+			top.Children = append(top.Children, n)
+			stack = append(stack, n)
+		default:
+			top.Children = append(top.Children, &PlanNode{Op: s})
+		}
+	}
+	if len(root.Children) == 1 {
+		return root.Children[0], nil
+	}
+	return root, nil
+}
+
+// Profile is the FT.PROFILE breakdown of one query.  Times are in
+// milliseconds.
+type Profile struct {
+	TotalTime    float64
+	ParsingTime  float64
+	PipelineTime float64        // pipeline creation
+	Iterators    *ProfileNode   // the index iterator tree
+	Processors   []*ProfileNode // result processors, in pipeline order
+}
+
+// ProfileNode is one iterator or result processor of a Profile.  Attrs
+// holds what else the server reports for it (Term, Query, Size, …).
+type ProfileNode struct {
+	Type     string
+	Time     float64
+	Counter  int64
+	Attrs    map[string]string
+	Children []*ProfileNode
+}
+
+// Profile runs the query under FT.PROFILE and returns where the time went.
+// The results themselves are discarded.
+func (b *SearchBuilder) Profile(ctx context.Context) (*Profile, error) {
+	if b.executor == nil {
+		return nil, ErrNoExecutor
+	}
+	args, err := b.RawArgs()
+	if err != nil {
+		return nil, err
+	}
+	return profile(ctx, b.executor, "SEARCH", args)
+}
+
+// Profile runs the aggregation under FT.PROFILE, see SearchBuilder.Profile.
+func (b *AggregateBuilder) Profile(ctx context.Context) (*Profile, error) {
+	if b.executor == nil {
+		return nil, ErrNoExecutor
+	}
+	args, err := b.RawArgs()
+	if err != nil {
+		return nil, err
+	}
+	return profile(ctx, b.executor, "AGGREGATE", args)
+}
+
+// profile wraps the FT.SEARCH / FT.AGGREGATE args in FT.PROFILE.
+func profile(ctx context.Context, exec driver.Executor, kind string, args []interface{}) (*Profile, error) {
+	cmd := append([]interface{}{"FT.PROFILE", args[1], kind, "QUERY"}, args[2:]...)
+	raw, err := exec.Do(ctx, cmd...)
+	if err != nil {
+		return nil, driver.Wrap(cmd, err)
+	}
+	return parseProfile(raw)
+}
+
+// parseProfile finds the profile sections by name anywhere in the reply,
+// which covers the RESP-2 pair lists and RESP-3 maps of the RediSearch
+// versions in use, with or without the per-shard wrapping.
+func parseProfile(raw any) (*Profile, error) {
+	var body any = raw
+	switch v := raw.(type) {
+	case []interface{}: // RESP-2: [results, profile]
+		if len(v) != 2 {
+			return nil, fmt.Errorf("query: unexpected FT.PROFILE reply of %d elements", len(v))
+		}
+		body = v[1]
+	case map[interface{}]interface{}: // RESP-3: {Results: …, Profile: …}
+		body = v["Profile"]
+	default:
+		return nil, fmt.Errorf("query: unexpected FT.PROFILE reply %T", raw)
+	}
+
+	p := &Profile{}
+	if v, ok := findKey(body, "Total profile time"); ok {
+		p.TotalTime = toFloat(first(v))
+	}
+	if v, ok := findKey(body, "Parsing time"); ok {
+		p.ParsingTime = toFloat(first(v))
+	}
+	if v, ok := findKey(body, "Pipeline creation time"); ok {
+		p.PipelineTime = toFloat(first(v))
+	}
+	if v, ok := findKey(body, "Iterators profile"); ok {
+		if nodes := profileNodes(v); len(nodes) > 0 {
+			p.Iterators = nodes[0]
+		}
+	}
+	if v, ok := findKey(body, "Result processors profile"); ok {
+		p.Processors = profileNodes(v)
+	}
+	return p, nil
+}
+
+// findKey returns the values of key in the first list or map holding it.
+// In a list that starts with key ([key, v1, v2, …]) every element after it
+// is a value; elsewhere in a list only the next one is.
+func findKey(v any, key string) ([]any, bool) {
+	switch t := v.(type) {
+	case map[interface{}]interface{}:
+		if x, ok := t[key]; ok {
+			return []any{x}, true
+		}
+		for _, x := range t {
+			if out, ok := findKey(x, key); ok {
+				return out, true
+			}
+		}
+	case []interface{}:
+		for i, x := range t {
+			if s, ok := x.(string); ok && s == key && i+1 < len(t) {
+				if i == 0 {
+					return t[1:], true
+				}
+				return t[i+1 : i+2], true
+			}
+		}
+		for _, x := range t {
+			if out, ok := findKey(x, key); ok {
+				return out, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// profileNodes decodes values that are either nodes or lists of nodes.
+func profileNodes(vals []any) []*ProfileNode {
+	var out []*ProfileNode
+	for _, v := range vals {
+		switch {
+		case isNode(v):
+			out = append(out, profileNode(v))
+		default:
+			if list, ok := v.([]interface{}); ok {
+				out = append(out, profileNodes(list)...)
+			}
+		}
+	}
+	return out
+}
+
+// isNode reports whether v is one iterator / processor: a map, or a flat
+// list of key/value pairs starting with a string key.
+func isNode(v any) bool {
+	switch t := v.(type) {
+	case map[interface{}]interface{}:
+		return true
+	case []interface{}:
+		if len(t) == 0 {
+			return false
+		}
+		_, ok := t[0].(string)
+		return ok
+	}
+	return false
+}
+
+// profileNode decodes one node; "Child iterators" become Children.
+func profileNode(v any) *ProfileNode {
+	n := &ProfileNode{Attrs: map[string]string{}}
+	set := func(k string, vals []any) {
+		switch k {
+		case "Type":
+			n.Type = fmt.Sprint(first(vals))
+		case "Time":
+			n.Time = toFloat(first(vals))
+		case "Counter":
+			n.Counter = int64(toFloat(first(vals)))
+		case "Child iterators", "Child iterator":
+			n.Children = append(n.Children, profileNodes(vals)...)
+		default:
+			n.Attrs[k] = fmt.Sprint(first(vals))
+		}
+	}
+	switch t := v.(type) {
+	case map[interface{}]interface{}:
+		for k, x := range t {
+			set(fmt.Sprint(k), []any{x})
+		}
+	case []interface{}:
+		for i := 0; i+1 < len(t); i += 2 {
+			k := fmt.Sprint(t[i])
+			if k == "Child iterators" || k == "Child iterator" { // the children end the list
+				set(k, t[i+1:])
+				break
+			}
+			set(k, t[i+1:i+2])
+		}
+	}
+	return n
+}
+
+func first(vals []any) any {
+	if len(vals) == 0 {
+		return nil
+	}
+	return vals[0]
+}
+
+func toFloat(v any) float64 {
+	switch t := v.(type) {
+	case float64:
+		return t
+	case int64:
+		return float64(t)
+	case string:
+		f, _ := strconv.ParseFloat(t, 64)
+		return f
+	}
+	return 0
+}
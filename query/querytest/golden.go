@@ -0,0 +1,147 @@
+// Package querytest snapshots the commands the query builders emit, so a
+// refactor of Compile or the builders cannot silently change them.
+//
+//	func TestQueries(t *testing.T) {
+//	    querytest.Golden(t, "testdata/queries.golden",
+//	        querytest.Expr("pending", q.Eq("status", "PENDING")),
+//	        querytest.Builder("by_promise", q.NewSearch("order_idx").
+//	            SortBy("promise_ts", q.Asc).Limit(0, 10)),
+//	    )
+//	}
+//
+// Run the tests with UPDATE_GOLDEN=1 to (re)write the golden file.
+package querytest
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	q "github.com/manojoshi/redisorm/query"
+)
+
+// UpdateEnv is the environment variable that rewrites golden files.
+const UpdateEnv = "UPDATE_GOLDEN"
+
+// Case is one named snapshot.
+type Case struct {
+	Name string
+	args func() ([]interface{}, error)
+}
+
+// Expr snapshots the compiled query string of e.
+func Expr(name string, e q.Expr) Case {
+	return Case{name, func() ([]interface{}, error) { return []interface{}{q.Compile(e)}, nil }}
+}
+
+// Builder snapshots b.RawArgs(); works with SearchBuilder, AggregateBuilder
+// and anything else exposing RawArgs.
+func Builder(name string, b interface{ RawArgs() ([]interface{}, error) }) Case {
+	return Case{name, b.RawArgs}
+}
+
+// Golden renders cases and compares them with the golden file at path,
+// reporting every differing case.  With UPDATE_GOLDEN=1 it writes the file
+// instead.
+func Golden(t testing.TB, path string, cases ...Case) {
+	t.Helper()
+	got, err := Render(cases...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if os.Getenv(UpdateEnv) != "" {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("querytest: %v (run with %s=1 to create it)", err, UpdateEnv)
+	}
+	if diff := Diff(string(want), got); diff != "" {
+		t.Errorf("querytest: %s differs from emitted commands (run with %s=1 to accept):\n%s",
+			path, UpdateEnv, diff)
+	}
+}
+
+// Render formats cases one per line as "name: arg arg …", quoting arguments
+// that contain spaces or quotes.
+func Render(cases ...Case) (string, error) {
+	var sb strings.Builder
+	seen := map[string]bool{}
+	for _, c := range cases {
+		if seen[c.Name] {
+			return "", fmt.Errorf("querytest: duplicate case %q", c.Name)
+		}
+		seen[c.Name] = true
+		args, err := c.args()
+		if err != nil {
+			return "", fmt.Errorf("querytest: %s: %w", c.Name, err)
+		}
+		sb.WriteString(c.Name)
+		sb.WriteByte(':')
+		for _, a := range args {
+			s := fmt.Sprint(a)
+			if s == "" || strings.ContainsAny(s, " \"\t\n") {
+				s = strconv.Quote(s)
+			}
+			sb.WriteByte(' ')
+			sb.WriteString(s)
+		}
+		sb.WriteByte('\n')
+	}
+	return sb.String(), nil
+}
+
+// Diff compares two renderings case by case; "" when they match.
+func Diff(want, got string) string {
+	w, err := parse(want)
+	if err != nil {
+		return err.Error()
+	}
+	g, _ := parse(got)
+	var sb strings.Builder
+	for _, name := range g.order {
+		switch wl, ok := w.lines[name]; {
+		case !ok:
+			fmt.Fprintf(&sb, "+ %s: %s\n", name, g.lines[name])
+		case wl != g.lines[name]:
+			fmt.Fprintf(&sb, "- %s: %s\n+ %s: %s\n", name, wl, name, g.lines[name])
+		}
+	}
+	for _, name := range w.order {
+		if _, ok := g.lines[name]; !ok {
+			fmt.Fprintf(&sb, "- %s: %s\n", name, w.lines[name])
+		}
+	}
+	return sb.String()
+}
+
+type rendering struct {
+	order []string
+	lines map[string]string
+}
+
+func parse(s string) (rendering, error) {
+	r := rendering{lines: map[string]string{}}
+	for _, line := range strings.Split(strings.TrimRight(s, "\n"), "\n") {
+		if line == "" {
+			continue
+		}
+		name, rest, ok := strings.Cut(line, ":")
+		if !ok {
+			return r, errors.New("querytest: malformed golden line " + strconv.Quote(line))
+		}
+		r.order = append(r.order, name)
+		r.lines[name] = strings.TrimPrefix(rest, " ")
+	}
+	return r, nil
+}
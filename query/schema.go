@@ -0,0 +1,251 @@
+package query
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/manojoshi/redisorm/scan"
+)
+
+// UnknownFieldError is returned by RawArgs (and so Run) of a builder bound
+// to a model with Schema when the command names fields the model does not
+// define – typos and renamed fields caught before Redis answers with an
+// opaque syntax error or, worse, silently matches nothing.
+type UnknownFieldError struct {
+	Model  string
+	Fields []string // each once, in order of appearance
+}
+
+func (e *UnknownFieldError) Error() string {
+	return fmt.Sprintf("query: %s has no field %s", e.Model, strings.Join(e.Fields, ", "))
+}
+
+// Unwrap makes errors.Is(err, ErrSyntax) hold.
+func (e *UnknownFieldError) Unwrap() error { return ErrSyntax }
+
+// schema is the set of field names a model defines.
+type schema struct {
+	model  string
+	fields map[string]bool
+}
+
+// newSchema lists the tagged fields of model, SHADOW ones under their
+// searchable "_num" name too.
+func newSchema(model any) *schema {
+	rt := reflect.TypeOf(model)
+	for rt != nil && rt.Kind() == reflect.Pointer {
+		rt = rt.Elem()
+	}
+	if rt == nil || rt.Kind() != reflect.Struct {
+		return nil
+	}
+	s := &schema{model: rt.String(), fields: map[string]bool{}}
+	for _, f := range scan.Fields(rt) {
+		s.fields[f.Name] = true
+		for _, a := range f.Attrs {
+			if strings.EqualFold(a, "SHADOW") {
+				s.fields[f.Name+"_num"] = true
+			}
+		}
+	}
+	return s
+}
+
+// timeCodecs maps the time.Time fields of model to their codec: unix
+// seconds, or milliseconds under UNIXMS.
+func timeCodecs(model any) map[string]scan.FieldCodec {
+	rt := reflect.TypeOf(model)
+	for rt != nil && rt.Kind() == reflect.Pointer {
+		rt = rt.Elem()
+	}
+	if rt == nil || rt.Kind() != reflect.Struct {
+		return nil
+	}
+	out := map[string]scan.FieldCodec{}
+	for _, f := range scan.Fields(rt) {
+		ft := f.Struct.Type
+		if ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		if ft != reflect.TypeOf(time.Time{}) {
+			continue
+		}
+		if c, ok := scan.CodecForField(f.Struct); ok {
+			out[f.Name] = c
+		}
+	}
+	return out
+}
+
+// encodeBounds writes the time.Time range bounds of where with the codec
+// of their field, where codecs has one; value() would use seconds.
+func encodeBounds(where Expr, codecs map[string]scan.FieldCodec) Expr {
+	if len(codecs) == 0 || where == nil {
+		return where
+	}
+	bound := func(c scan.FieldCodec, v any) any {
+		if t, ok := v.(time.Time); ok {
+			if s, err := c.Encode(reflect.ValueOf(t)); err == nil {
+				return s
+			}
+		}
+		return v
+	}
+	return Rewrite(where, func(x Expr) Expr {
+		n, ok := x.(*rng)
+		if !ok {
+			return x
+		}
+		c, ok := codecs[bare(n.f)]
+		if !ok {
+			return x
+		}
+		cp := *n
+		cp.lo, cp.hi = bound(c, n.lo), bound(c, n.hi)
+		return &cp
+	})
+}
+
+// check returns an UnknownFieldError for the names (with or without "@")
+// that are neither model fields nor in also (aliases), nil if there are
+// none.
+func (s *schema) check(names []string, also map[string]bool) error {
+	var unknown []string
+	seen := map[string]bool{}
+	for _, n := range names {
+		n = strings.TrimPrefix(n, "@")
+		if n == "" || s.fields[n] || also[n] || seen[n] {
+			continue
+		}
+		seen[n] = true
+		unknown = append(unknown, n)
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+	return &UnknownFieldError{Model: s.model, Fields: unknown}
+}
+
+// Schema binds the builder to model, a struct tagged with redisorm: every
+// field the search names – in Where / AndWhere, Select, SortBy, InFields,
+// Highlight, Summarize and Dedup – must be one of its fields, or RawArgs
+// fails with an *UnknownFieldError.  A KNN score alias may be sorted on.
+// Schema implies Model.
+func (b *SearchBuilder) Schema(model any) *SearchBuilder {
+	b.schema = newSchema(model)
+	return b.Model(model)
+}
+
+// Model binds the builder to model for encoding only: time.Time range
+// bounds on its fields are written with the field's codec, so
+// Gt("created_at", t) compares milliseconds on a UNIXMS field.  Unbound
+// builders write time.Time as unix seconds; pass t.UnixMilli() for UNIXMS
+// fields there.
+func (b *SearchBuilder) Model(model any) *SearchBuilder {
+	b.codecs = timeCodecs(model)
+	return b
+}
+
+// checkSchema validates the fields of the search against the bound model.
+func (b *SearchBuilder) checkSchema(where Expr) error {
+	if b.schema == nil {
+		return nil
+	}
+	names := Fields(where)
+	names = append(names, b.returnFields...)
+	names = append(names, b.inFields...)
+	names = append(names, clauseFields(b.summarize)...)
+	names = append(names, clauseFields(b.highlight)...)
+	if b.sortField != "" {
+		names = append(names, b.sortField)
+	}
+	if b.dedupField != "" {
+		names = append(names, b.dedupField)
+	}
+	aliases := map[string]bool{}
+	Walk(where, func(x Expr) bool {
+		if n, ok := x.(*knn); ok {
+			aliases[n.alias] = true
+		}
+		return true
+	})
+	return b.schema.check(names, aliases)
+}
+
+// clauseFields returns the names of a "FIELDS n f1 … fn" section of a
+// HIGHLIGHT / SUMMARIZE clause.
+func clauseFields(clause []interface{}) []string {
+	for i, a := range clause {
+		if a != "FIELDS" || i+1 >= len(clause) {
+			continue
+		}
+		n, _ := strconv.Atoi(fmt.Sprint(clause[i+1]))
+		var out []string
+		for _, f := range clause[i+2 : min(i+2+n, len(clause))] {
+			out = append(out, fmt.Sprint(f))
+		}
+		return out
+	}
+	return nil
+}
+
+// Schema binds the aggregation to model, as on SearchBuilder: the fields of
+// Where / AndWhere, plain GroupBy keys, APPLY inputs, reducer arguments and
+// SortBy keys must be model fields or aliases defined by the pipeline.
+// Filter expressions and ByExpr keys are not parsed.  Schema implies Model.
+func (b *AggregateBuilder) Schema(model any) *AggregateBuilder {
+	b.schema = newSchema(model)
+	return b.Model(model)
+}
+
+// Model binds the aggregation to model for encoding only, as on
+// SearchBuilder.
+func (b *AggregateBuilder) Model(model any) *AggregateBuilder {
+	b.codecs = timeCodecs(model)
+	return b
+}
+
+// checkSchema validates the fields of the aggregation against the bound
+// model.
+func (b *AggregateBuilder) checkSchema(where Expr) error {
+	if b.schema == nil {
+		return nil
+	}
+	names := Fields(where)
+	aliases := map[string]bool{}
+	for _, g := range append(b.applies[:len(b.applies):len(b.applies)], b.groups...) {
+		if g.alias != "" {
+			aliases[g.alias] = true
+		}
+		names = append(names, g.load...)
+		if g.apply == "" && isFieldRef(g.raw) {
+			names = append(names, g.raw)
+		}
+	}
+	for _, g := range b.postApplies { // read group keys and reducer aliases only
+		aliases[g.alias] = true
+	}
+	for _, r := range b.reducers {
+		if r.alias != "" {
+			aliases[r.alias] = true
+		}
+		for _, a := range r.args {
+			if isFieldRef(a) {
+				names = append(names, a)
+			}
+		}
+	}
+	for _, k := range b.sorts {
+		names = append(names, k.Field)
+	}
+	return b.schema.check(names, aliases)
+}
+
+// isFieldRef reports whether s is a plain "@name" reference.
+func isFieldRef(s string) bool {
+	name, ok := strings.CutPrefix(s, "@")
+	return ok && name != "" && !strings.ContainsAny(name, " ()+-*/,'\"")
+}
@@ -0,0 +1,55 @@
+package query
+
+import (
+	"context"
+	"iter"
+
+	"github.com/manojoshi/redisorm/driver"
+	"github.com/manojoshi/redisorm/scan"
+)
+
+// Stream runs the aggregate through a cursor and yields its rows, reading
+// further pages with FT.CURSOR READ until the cursor is exhausted.  The
+// cursor count defaults to 1000 when WithCursor was not called.  Stopping
+// early deletes the server-side cursor; an error is yielded once, last.
+func (b *AggregateBuilder) Stream(ctx context.Context) iter.Seq2[map[string]string, error] {
+	return func(yield func(map[string]string, error) bool) {
+		if b.executor == nil {
+			yield(nil, ErrNoExecutor)
+			return
+		}
+		if b.cursorCount == 0 {
+			b.cursorCount = 1000
+		}
+		args, err := b.RawArgs()
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		raw, err := b.executor.Do(ctx, args...)
+		if err != nil {
+			yield(nil, driver.Wrap(args, err))
+			return
+		}
+		page, cursor, err := driver.SplitCursorReply(raw)
+		for err == nil {
+			var rows []map[string]string
+			if rows, err = scan.DecodeMaps(page); err != nil {
+				break
+			}
+			for _, row := range rows {
+				if !yield(row, nil) {
+					if cursor != 0 {
+						_ = driver.CursorDel(context.WithoutCancel(ctx), b.executor, b.idx, cursor)
+					}
+					return
+				}
+			}
+			if cursor == 0 {
+				return
+			}
+			page, cursor, err = driver.CursorRead(ctx, b.executor, b.idx, cursor, b.cursorCount)
+		}
+		yield(nil, err)
+	}
+}
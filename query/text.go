@@ -0,0 +1,74 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Text("red shoes")  ➜ "red shoes"   (full-text, all TEXT fields)
+func Text(terms string) Expr { return &text{terms: terms} }
+
+// Match("title", "red shoes")  ➜ "@title:(red shoes)"
+// Words are escaped, so punctuation in user input is matched literally
+// rather than parsed as query syntax.
+func Match(field string, terms string) Expr { return &text{f: field, terms: terms} }
+
+type text struct{ f, terms string }
+
+// Raw("@title:(red|blue) ~@tags:{sale}")  ➜ sent verbatim
+// An escape hatch for RediSearch syntax the AST does not cover; nothing is
+// escaped or checked.  Combined with other nodes it is parenthesised, as the
+// whole query it is sent as is (so "*=>[KNN …]" works).  Bind $params with
+// SearchBuilder.Params, which also selects DIALECT 2.
+func Raw(query string) Expr { return raw(query) }
+
+type raw string
+
+func (n raw) compile(sb *strings.Builder) {
+	sb.WriteByte('(')
+	sb.WriteString(string(n))
+	sb.WriteByte(')')
+}
+
+func (n *text) compile(sb *strings.Builder) {
+	if n.f == "" {
+		sb.WriteString(n.terms)
+		return
+	}
+	words := strings.Fields(n.terms)
+	for i, w := range words {
+		words[i] = escapeTerm(w)
+	}
+	fmt.Fprintf(sb, "%s:(%s)", field(n.f), strings.Join(words, " "))
+}
+
+// Correct returns e with the words of its Text / Match nodes replaced per
+// corrections (misspelling → replacement, lower-case keys), e.g. from
+// FT.SPELLCHECK.  Other nodes are kept as they are.
+func Correct(e Expr, corrections map[string]string) Expr {
+	switch n := e.(type) {
+	case *text:
+		words := strings.Fields(n.terms)
+		for i, w := range words {
+			if c, ok := corrections[strings.ToLower(w)]; ok {
+				words[i] = c
+			}
+		}
+		return &text{f: n.f, terms: strings.Join(words, " ")}
+	case *and:
+		return &and{correctAll(n.xs, corrections)}
+	case *or:
+		return &or{correctAll(n.xs, corrections)}
+	case *not:
+		return &not{Correct(n.x, corrections)}
+	}
+	return e
+}
+
+func correctAll(xs []Expr, corrections map[string]string) []Expr {
+	out := make([]Expr, len(xs))
+	for i, x := range xs {
+		out[i] = Correct(x, corrections)
+	}
+	return out
+}
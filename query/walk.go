@@ -0,0 +1,154 @@
+package query
+
+import "strings"
+
+// Kind names the type of an Expr node.
+type Kind string
+
+const (
+	KindMatchAll  Kind = "MATCHALL"  // MatchAll
+	KindEq        Kind = "EQ"        // Eq: Values = [v]
+	KindIn        Kind = "IN"        // In: Values = vs
+	KindRange     Kind = "RANGE"     // Range, Gt, …, Between: Values = [lo, hi]
+	KindPrefix    Kind = "PREFIX"    // Prefix: Values = [prefix]
+	KindFuzzy     Kind = "FUZZY"     // Fuzzy: Values = [term, distance]
+	KindWildcard  Kind = "WILDCARD"  // Wildcard: Values = [pattern]
+	KindText      Kind = "TEXT"      // Text, Match: Values = [terms]
+	KindRaw       Kind = "RAW"       // Raw: Values = [query]
+	KindGeoRadius Kind = "GEORADIUS" // GeoRadius: Values = [lon, lat, radius, unit]
+	KindGeoBox    Kind = "GEOBOX"    // GeoBox: Values = [minLon, minLat, maxLon, maxLat]
+	KindKNN       Kind = "KNN"       // KNN: Values = [k], Children = [filter] if any
+	KindAnd       Kind = "AND"       // And: Children = operands
+	KindOr        Kind = "OR"        // Or: Children = operands
+	KindNot       Kind = "NOT"       // Not: Children = [operand]
+)
+
+// Node is the read-only view of one Expr node, for middleware that needs
+// to look inside a query: audit the fields it touches, reject some, or
+// decide how to rewrite it (see Rewrite).
+type Node struct {
+	Kind           Kind
+	Field          string // without "@"; "" for combinators, Text and Raw
+	Values         []any  // see the Kind constants
+	LoOpen, HiOpen bool   // Range: exclusive bounds
+	Children       []Expr
+}
+
+// Inspect describes e.  Expressions from outside this package have an
+// empty Kind.
+func Inspect(e Expr) Node {
+	switch n := e.(type) {
+	case matchAll:
+		return Node{Kind: KindMatchAll}
+	case *eq:
+		return Node{Kind: KindEq, Field: bare(n.f), Values: []any{n.v}}
+	case *in:
+		return Node{Kind: KindIn, Field: bare(n.f), Values: append([]any(nil), n.vs...)}
+	case *rng:
+		return Node{Kind: KindRange, Field: bare(n.f), Values: []any{n.lo, n.hi}, LoOpen: n.loOpen, HiOpen: n.hiOpen}
+	case *pfx:
+		return Node{Kind: KindPrefix, Field: bare(n.f), Values: []any{n.p}}
+	case *fuzzy:
+		return Node{Kind: KindFuzzy, Field: bare(n.f), Values: []any{n.term, n.dist}}
+	case *wildcard:
+		return Node{Kind: KindWildcard, Field: bare(n.f), Values: []any{n.pattern}}
+	case *text:
+		return Node{Kind: KindText, Field: bare(n.f), Values: []any{n.terms}}
+	case raw:
+		return Node{Kind: KindRaw, Values: []any{string(n)}}
+	case *geoRadius:
+		return Node{Kind: KindGeoRadius, Field: bare(n.f), Values: []any{n.lon, n.lat, n.rad, n.unit}}
+	case *geoBox:
+		return Node{Kind: KindGeoBox, Field: bare(n.f), Values: []any{n.minLon, n.minLat, n.maxLon, n.maxLat}}
+	case *knn:
+		node := Node{Kind: KindKNN, Field: bare(n.f), Values: []any{n.k}}
+		if n.filter != nil {
+			node.Children = []Expr{n.filter}
+		}
+		return node
+	case *and:
+		return Node{Kind: KindAnd, Children: append([]Expr(nil), n.xs...)}
+	case *or:
+		return Node{Kind: KindOr, Children: append([]Expr(nil), n.xs...)}
+	case *not:
+		return Node{Kind: KindNot, Children: []Expr{n.x}}
+	}
+	return Node{}
+}
+
+func bare(f string) string { return strings.TrimPrefix(f, "@") }
+
+// Walk calls fn for e and, depth first, every node below it; when fn
+// returns false the node's children are skipped.
+func Walk(e Expr, fn func(Expr) bool) {
+	if e == nil || !fn(e) {
+		return
+	}
+	for _, c := range Inspect(e).Children {
+		Walk(c, fn)
+	}
+}
+
+// Fields returns the fields e filters on, each once, in order of
+// appearance and without "@".
+func Fields(e Expr) []string {
+	var out []string
+	seen := map[string]bool{}
+	Walk(e, func(x Expr) bool {
+		if f := Inspect(x).Field; f != "" && !seen[f] {
+			seen[f] = true
+			out = append(out, f)
+		}
+		return true
+	})
+	return out
+}
+
+// Rewrite rebuilds e bottom-up: every node's children are rewritten first,
+// then fn gets the node and returns its replacement – itself to keep it,
+// nil to drop it.  And / Or drop nil operands (becoming MatchAll when none
+// remain), Not of a dropped operand is dropped too, and a KNN whose filter
+// is dropped searches all documents.  e itself is never modified.
+//
+//	// strip conditions on fields the caller may not filter by
+//	where = q.Rewrite(where, func(x q.Expr) q.Expr {
+//	    if q.Inspect(x).Field == "cost_price" {
+//	        return nil
+//	    }
+//	    return x
+//	})
+func Rewrite(e Expr, fn func(Expr) Expr) Expr {
+	switch n := e.(type) {
+	case nil:
+		return nil
+	case *and:
+		e = AndSkipNil(rewriteAll(n.xs, fn)...)
+	case *or:
+		e = OrSkipNil(rewriteAll(n.xs, fn)...)
+	case *not:
+		x := Rewrite(n.x, fn)
+		if x == nil {
+			return nil
+		}
+		if x != n.x {
+			e = &not{x}
+		}
+	case *knn:
+		if n.filter != nil {
+			if f := Rewrite(n.filter, fn); f != n.filter {
+				c := *n
+				c.filter = f
+				e = &c
+			}
+		}
+	}
+	return fn(e)
+}
+
+func rewriteAll(xs []Expr, fn func(Expr) Expr) []Expr {
+	out := make([]Expr, len(xs))
+	for i, x := range xs {
+		out[i] = Rewrite(x, fn)
+	}
+	return out
+}
@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/manojoshi/redisorm/driver"
+	q "github.com/manojoshi/redisorm/query"
+	"github.com/manojoshi/redisorm/scan"
+)
+
+// QuerySpec describes one FT.SEARCH of a BatchSearch call.
+type QuerySpec struct {
+	Index string
+	Where q.Expr
+	Opts  []Opt
+}
+
+// PageResult is the decoded outcome of a single QuerySpec.  Err is set when
+// that query alone failed (syntax error, unknown index, …); the other results
+// of the batch are still valid.
+type PageResult struct {
+	Docs  []map[string]string
+	Total int
+	Err   error
+}
+
+// BatchSearch pipelines every query in one round trip and decodes each reply.
+// The returned slice is index-aligned with queries.  The error is non-nil
+// only when the batch could not be sent at all.
+func (r *Repo) BatchSearch(ctx context.Context, queries []QuerySpec) ([]PageResult, error) {
+	out := make([]PageResult, len(queries))
+	cmds := make([][]interface{}, 0, len(queries))
+	slots := make([]int, 0, len(queries)) // cmds[i] answers queries[slots[i]]
+	builders := make([]*q.SearchBuilder, len(queries))
+	for i, spec := range queries {
+		builders[i] = r.searchBuilder(spec.Index, spec.Where, spec.Opts)
+		args, err := builders[i].RawArgs()
+		if err != nil {
+			out[i].Err = err
+			continue
+		}
+		cmds = append(cmds, args)
+		slots = append(slots, i)
+	}
+	if len(cmds) == 0 {
+		return out, nil
+	}
+
+	replies, err := driver.DoBatch(ctx, r.exec, cmds)
+	if err != nil {
+		return nil, err
+	}
+	for i, reply := range replies {
+		res := &out[slots[i]]
+		if e, ok := reply.(error); ok {
+			res.Err = e
+			continue
+		}
+		if res.Docs, res.Err = builders[slots[i]].Decode(reply); res.Err != nil {
+			continue
+		}
+		res.Total, res.Err = scan.Total(reply)
+	}
+	return out, nil
+}
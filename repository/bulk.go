@@ -0,0 +1,191 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/manojoshi/redisorm/driver"
+	q "github.com/manojoshi/redisorm/query"
+	"github.com/manojoshi/redisorm/scan"
+)
+
+// BulkOpt tunes DeleteWhere and UpdateWhere.
+type BulkOpt func(*bulkCfg)
+
+type bulkCfg struct {
+	batch int // keys per search page and per write pipeline
+}
+
+// defaultBulkBatch is the page / pipeline size of bulk operations.
+const defaultBulkBatch = 500
+
+// BulkBatch sets how many keys DeleteWhere and UpdateWhere fetch per
+// search page or cursor read and write per pipeline (default 500).
+func BulkBatch(n int) BulkOpt { return func(c *bulkCfg) { c.batch = n } }
+
+func newBulkCfg(opts []BulkOpt) *bulkCfg {
+	cfg := &bulkCfg{batch: defaultBulkBatch}
+	for _, o := range opts {
+		o(cfg)
+	}
+	if cfg.batch <= 0 {
+		cfg.batch = defaultBulkBatch
+	}
+	return cfg
+}
+
+// DeleteWhere deletes every document matching where and returns how many
+// were removed.  Keys are found with NOCONTENT searches and deleted in
+// pipelined batches; each page is re-queried from the start, since deleted
+// documents drop out of the index.  Soft-deleting models are stamped
+// instead, as by Delete.
+//
+//	n, err := repo.DeleteWhere(ctx, q.Eq("status", "CANCELLED"))
+func (r *Repository) DeleteWhere(ctx context.Context, where q.Expr, opts ...BulkOpt) (int, error) {
+	if f, ok := r.softField(nil); ok {
+		stamp, err := softStamp(f, time.Now())
+		if err != nil {
+			return 0, err
+		}
+		return r.UpdateWhere(ctx, where, map[string]any{f.Name: json.Number(stamp)}, opts...)
+	}
+	return r.deleteWhere(ctx, r.scoped(where, nil, nil), newBulkCfg(opts))
+}
+
+func (r *Repository) deleteWhere(ctx context.Context, where q.Expr, cfg *bulkCfg) (int, error) {
+	deleted := 0
+	for {
+		keys, err := r.pageKeys(ctx, where, 0, cfg.batch)
+		if err != nil || len(keys) == 0 {
+			return deleted, err
+		}
+		cmds := make([][]interface{}, len(keys))
+		for i, k := range keys {
+			cmds[i] = []interface{}{"DEL", k}
+		}
+		n, err := r.bulkWrite(ctx, cmds)
+		deleted += n
+		if err != nil {
+			return deleted, err
+		}
+		if n == 0 { // the index lags behind the keyspace; don't spin
+			return deleted, nil
+		}
+	}
+}
+
+// UpdateWhere sets changes (redisorm field names → values, as for Update)
+// on every live (not soft-deleted) document matching where and returns how
+// many were updated.
+// All matching keys are collected first, through an FT.AGGREGATE cursor
+// that is not bound by MAXSEARCHRESULTS the way deep FT.SEARCH offsets
+// are, so changes that make documents stop matching do not disturb the
+// paging.  Documents deleted before their write are skipped, not
+// recreated, and not counted.
+//
+//	n, err := repo.UpdateWhere(ctx, q.Lt("promise_ts", cutoff),
+//	    map[string]any{"status": "LATE"})
+func (r *Repository) UpdateWhere(ctx context.Context, where q.Expr, changes map[string]any, opts ...BulkOpt) (int, error) {
+	cfg := newBulkCfg(opts)
+	if len(changes) == 0 {
+		return 0, nil
+	}
+	vals, err := r.changeValues(changes)
+	if err != nil {
+		return 0, err
+	}
+	where = r.scoped(where, nil, nil)
+	var keys []string
+	for row, err := range r.newAggregate(where).
+		Load(scan.KeyField).
+		WithCursor(cfg.batch).
+		Using(r.exec).
+		Stream(ctx) {
+		if err != nil {
+			return 0, err
+		}
+		keys = append(keys, row[scan.KeyField])
+	}
+
+	updated := 0
+	for start := 0; start < len(keys); start += cfg.batch {
+		var cmds [][]interface{}
+		for _, k := range keys[start:min(start+cfg.batch, len(keys))] {
+			c, err := r.changeCmd(k, vals)
+			if err != nil {
+				return updated, err
+			}
+			cmds = append(cmds, c)
+		}
+		res, err := driver.DoBatch(ctx, r.exec, cmds)
+		if err != nil {
+			return updated, err
+		}
+		for _, reply := range res { // nil: deleted since its key was collected
+			switch v := reply.(type) {
+			case error:
+				if !errors.Is(v, redis.Nil) {
+					return updated, v
+				}
+			case nil:
+			default:
+				updated++
+			}
+		}
+	}
+	return updated, nil
+}
+
+// pageKeys runs one NOCONTENT page of where.
+func (r *Repository) pageKeys(ctx context.Context, where q.Expr, offset, limit int) ([]string, error) {
+	return r.newSearch(where).
+		Limit(offset, limit).
+		Using(r.exec).
+		Keys(ctx)
+}
+
+// changeCmd is the script setting vals (see changeValues) on key if it
+// still exists, so a document deleted since the search is not recreated
+// as a partial one; its reply is nil then.  The version is bumped as by
+// Update.
+func (r *Repository) changeCmd(key string, vals map[string]any) ([]interface{}, error) {
+	var cmds [][]interface{}
+	if r.json {
+		for path, v := range vals {
+			b, err := json.Marshal(v)
+			if err != nil {
+				return nil, fmt.Errorf("repository: %s: %w", path, err)
+			}
+			cmds = append(cmds, []interface{}{"JSON.SET", key, path, string(b)})
+		}
+	} else {
+		cmds = append(cmds, hset(key, vals))
+	}
+	if bump, ok := r.versionBump(nil, key); ok {
+		cmds = append(cmds, bump)
+	}
+	return scriptWrite(key, true, cmds), nil
+}
+
+// bulkWrite pipelines cmds and sums their integer replies (DEL counts).
+func (r *Repository) bulkWrite(ctx context.Context, cmds [][]interface{}) (int, error) {
+	res, err := driver.DoBatch(ctx, r.exec, cmds)
+	if err != nil {
+		return 0, err
+	}
+	n := 0
+	for _, reply := range res {
+		switch v := reply.(type) {
+		case error:
+			return n, v
+		case int64:
+			n += int(v)
+		}
+	}
+	return n, nil
+}
@@ -59,62 +59,99 @@ func (r *Repo) DropIndex(ctx context.Context, indexName string, prefixes ...stri
 └───────────────────────────────────────────────────────────────*/
 
 // LoadHash inserts one record into a HASH (field tags drive column names).
-func (r *Repo) LoadHash(ctx context.Context, key string, record any) error {
-	if r.raw == nil {
-		return fmt.Errorf("repository: raw Redis client not configured")
+func (r *Repo) LoadHash(ctx context.Context, key string, record any, opts ...WriteOpt) error {
+	vals, err := structToMap(record)
+	if err != nil {
+		return err
 	}
-	vals := structToMap(record)
-	return r.raw.HSet(ctx, key, vals).Err()
+	return writeDoc(ctx, r.exec, key, record, newWriteCfg(opts), true,
+		payloadSize(key, vals), hset(key, vals))
 }
 
-// LoadBulk writes many records; prefix is used if keyFn returns only ID.
-func (r *Repo) LoadBulk(
-	ctx context.Context,
-	indexName string,
-	prefix string,
-	records []any,
-	keyFn func(any) string,
-) error {
-	for _, rec := range records {
-		key := keyFn(rec)
-		if !strings.HasPrefix(key, prefix) {
-			key = prefix + key
-		}
-		if err := r.LoadHash(ctx, key, rec); err != nil {
-			return err
-		}
-	}
-	return nil
+// Delete removes the hash stored at key.  With SkipSeenCuckoo the key is
+// also removed from the filter, so a later write of it is not skipped.
+func (r *Repo) Delete(ctx context.Context, key string, opts ...WriteOpt) error {
+	return writeDoc(ctx, r.exec, key, nil, newWriteCfg(opts), false,
+		len(key), []interface{}{"DEL", key})
 }
 
 // Generic Search / Aggregate
 // Search and Aggregate are generic methods that work with any model type.
 
-// Search over any model
+// Search over any model.  The results are *scan.Doc values boxed as any.
+//
+// Deprecated: use SearchAs, which decodes into a concrete type.
 func (r *Repo) Search(
 	ctx context.Context,
 	indexName string,
 	where q.Expr,
 	opts ...Opt,
 ) ([]any, error) {
-	sb := q.NewSearch(indexName).Using(r.exec)
-	if where != nil {
-		sb.Where(where)
+	sb := r.searchBuilder(indexName, where, opts)
+	raw, err := sb.RawArgs()
+	if err != nil {
+		return nil, err
 	}
-	for _, o := range opts {
-		o.applySearch(sb)
+	resp, err := r.exec.Do(ctx, raw...)
+	if err != nil {
+		return nil, driver.Wrap(raw, err)
 	}
+	docs, err := sb.Decode(resp)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]any, len(docs))
+	for i, d := range docs {
+		out[i] = d
+	}
+	return out, nil
+}
+
+// SearchAs runs a FT.SEARCH on indexName through repo and decodes the hits
+// into T (a struct tagged with redisorm, map[string]string or *scan.Doc).
+// Fields tagged __key / __score receive the document key and score.
+//
+//	orders, err := repository.SearchAs[Order](ctx, repo, "order_idx",
+//	    q.Eq("status", "PENDING"), repository.Limit(0, 100))
+func SearchAs[T any](ctx context.Context, repo *Repo, indexName string, where q.Expr, opts ...Opt) ([]T, error) {
+	sb := repo.searchBuilder(indexName, where, opts)
 	raw, err := sb.RawArgs()
 	if err != nil {
 		return nil, err
 	}
-	resp, err := r.exec.Do(ctx, raw...)
+	resp, err := repo.exec.Do(ctx, raw...)
+	if err != nil {
+		return nil, driver.Wrap(raw, err)
+	}
+	hits, err := sb.DecodeHits(resp)
 	if err != nil {
 		return nil, err
 	}
-	return scan.DecodeSlice[any](resp)
+	if _, ok := any((*T)(nil)).(**scan.Doc); ok {
+		docs := make([]*scan.Doc, len(hits))
+		for i, h := range hits {
+			docs[i] = scan.NewDoc(h.Fields)
+		}
+		return any(docs).([]T), nil
+	}
+	return scan.Docs[T](hits)
 }
 
+// searchBuilder prepares a FT.SEARCH for indexName with opts applied.
+func (r *Repo) searchBuilder(indexName string, where q.Expr, opts []Opt) *q.SearchBuilder {
+	sb := q.NewSearch(indexName).Using(r.exec)
+	if where != nil {
+		sb.Where(where)
+	}
+	for _, o := range opts {
+		o.applySearch(sb)
+	}
+	return sb
+}
+
+// Aggregate runs FT.AGGREGATE on indexName grouped by groupBy.  Apply
+// options run before that grouping; for APPLY stages over the reducer
+// output, leave groupBy empty and pass Group among opts instead.
 func (r *Repo) Aggregate(
 	ctx context.Context,
 	indexName string,
@@ -124,14 +161,16 @@ func (r *Repo) Aggregate(
 ) ([]map[string]string, error) {
 
 	ab := q.NewAggregate(indexName).
-		Using(r.exec).
-		GroupBy(groupBy...)
+		Using(r.exec)
 	if where != nil {
 		ab.Where(where)
 	}
 	for _, o := range opts {
 		o.applyAgg(ab)
 	}
+	if len(groupBy) > 0 { // after opts, so their Apply stages feed the keys
+		ab.GroupBy(groupBy...)
+	}
 
 	rawArgs, err := ab.RawArgs()
 	if err != nil {
@@ -139,13 +178,19 @@ func (r *Repo) Aggregate(
 	}
 	resp, err := r.exec.Do(ctx, rawArgs...)
 	if err != nil {
-		return nil, err
+		return nil, driver.Wrap(rawArgs, err)
 	}
 	return scan.DecodeMaps(resp)
 }
 
-// structToMap converts a struct or map to a map[string]any.
-func structToMap(v any) map[string]any {
+// Encode returns the hash fields LoadHash writes for record, for callers
+// that write through their own pipeline (fixtures, importers).
+func Encode(record any) (map[string]any, error) { return structToMap(record) }
+
+// structToMap converts a struct or map to a map[string]any.  Fields whose
+// type has a scan.FieldCodec are stored in their codec form; SHADOW fields
+// additionally get their NUMERIC "<name>_num" sibling.
+func structToMap(v any) (map[string]any, error) {
 	rv := reflect.ValueOf(v)
 	if rv.Kind() == reflect.Pointer {
 		rv = rv.Elem()
@@ -157,20 +202,46 @@ func structToMap(v any) map[string]any {
 		for iter.Next() {
 			out[fmt.Sprint(iter.Key())] = iter.Value().Interface()
 		}
-		return out
+		return out, nil
 	}
 
 	// struct: use redisorm tags
-	rt := rv.Type()
-	out := make(map[string]any, rt.NumField())
-	for i := 0; i < rt.NumField(); i++ {
-		f := rt.Field(i)
-		tag := f.Tag.Get("redisorm")
-		if tag == "" {
+	fields := scan.Fields(rv.Type()) // embedded / nested fragments flattened
+	out := make(map[string]any, len(fields))
+	for _, sf := range fields {
+		f, name := sf.Struct, sf.Name
+		if scan.MetaField(name) {
+			continue
+		}
+		parts := append([]string{name}, sf.Attrs...)
+		fv := rv.FieldByIndex(sf.Index)
+
+		codec, ok := scan.CodecForField(f)
+		if !ok {
+			out[name] = fv.Interface()
 			continue
 		}
-		name := strings.TrimPrefix(strings.Split(tag, ",")[0], "@")
-		out[name] = rv.Field(i).Interface()
+		s, err := codec.Encode(fv)
+		if err != nil {
+			return nil, fmt.Errorf("repository: field %s: %w", f.Name, err)
+		}
+		out[name] = s
+		if nc, ok := codec.(scan.NumericCodec); ok && hasAttr(parts[1:], "SHADOW") {
+			n, err := nc.Float64(fv)
+			if err != nil {
+				return nil, fmt.Errorf("repository: field %s: %w", f.Name, err)
+			}
+			out[name+index.ShadowSuffix] = n
+		}
+	}
+	return out, nil
+}
+
+func hasAttr(attrs []string, want string) bool {
+	for _, a := range attrs {
+		if strings.EqualFold(a, want) {
+			return true
+		}
 	}
-	return out
+	return false
 }
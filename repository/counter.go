@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/manojoshi/redisorm/driver"
+	"github.com/manojoshi/redisorm/scan"
+)
+
+// Incr atomically adds delta to the NUMERIC field of the entity with
+// primary key id (or of the entity itself) and returns the new value –
+// HINCRBY, or JSON.NUMINCRBY for AsJSON repositories:
+//
+//	stock, err := repo.Incr(ctx, "sku-1", "qty", -2)
+//
+// When the model is known – a ForModel repository, or an entity passed as
+// id – field must be one of its NUMERIC fields; SHADOW fields, stored as
+// exact strings, cannot be incremented.  On VERSION models the version is
+// bumped in the same script.
+func (r *Repository) Incr(ctx context.Context, id any, field string, delta int64) (int64, error) {
+	model := reflect.TypeOf(id)
+	if model != nil && model.Kind() == reflect.Pointer {
+		model = model.Elem()
+	}
+	if model == nil || model.Kind() != reflect.Struct {
+		model = reflect.TypeOf(r.model)
+	}
+	path := "$." + field
+	if model != nil {
+		f, err := counterField(model, field)
+		if err != nil {
+			return 0, err
+		}
+		path = "$." + strings.Join(f.JSON, ".")
+	}
+	key, err := r.idKey(id, model)
+	if err != nil {
+		return 0, err
+	}
+
+	args := []interface{}{"HINCRBY", key, field, delta}
+	if r.json {
+		args = []interface{}{"JSON.NUMINCRBY", key, path, delta}
+	}
+	args = r.versioned(model, key, [][]interface{}{args})[0]
+	raw, err := r.exec.Do(ctx, args...)
+	if err != nil {
+		return 0, driver.Wrap(args, err)
+	}
+	return counterValue(raw)
+}
+
+// Decr is Incr with -delta.
+func (r *Repository) Decr(ctx context.Context, id any, field string, delta int64) (int64, error) {
+	return r.Incr(ctx, id, field, -delta)
+}
+
+// counterField finds field among model's tagged fields and checks it can be
+// incremented.
+func counterField(model reflect.Type, field string) (scan.Field, error) {
+	for _, f := range scan.Fields(model) {
+		if f.Name != field {
+			continue
+		}
+		numeric := false
+		for _, a := range f.Attrs {
+			switch strings.ToUpper(a) {
+			case "NUMERIC":
+				numeric = true
+			case "SHADOW":
+				return f, fmt.Errorf("repository: %s.%s is SHADOW; its exact value cannot be incremented", model.Name(), field)
+			}
+		}
+		if !numeric {
+			return f, fmt.Errorf("repository: %s.%s is not a NUMERIC field", model.Name(), field)
+		}
+		return f, nil
+	}
+	return scan.Field{}, fmt.Errorf("repository: %s has no field %q", model.Name(), field)
+}
+
+// counterValue decodes HINCRBY's integer or JSON.NUMINCRBY's per-path array
+// ("[5]" on RESP-2, [5] on RESP-3).
+func counterValue(raw any) (int64, error) {
+	switch v := raw.(type) {
+	case int64:
+		return v, nil
+	case string:
+		var vals []json.Number
+		if err := json.Unmarshal([]byte(v), &vals); err != nil || len(vals) == 0 {
+			return 0, fmt.Errorf("repository: unexpected counter reply %q", v)
+		}
+		return vals[0].Int64()
+	case []interface{}:
+		if len(v) > 0 {
+			switch n := v[0].(type) {
+			case int64:
+				return n, nil
+			case float64:
+				return int64(n), nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("repository: unexpected counter reply %T", raw)
+}
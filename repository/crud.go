@@ -0,0 +1,226 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/manojoshi/redisorm/driver"
+	"github.com/manojoshi/redisorm/scan"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Save writes entity to its hash, replacing the fields it sets.  The key
+// comes from the repository's key scheme (WithPrefix / WithKeyTemplate); an
+// empty PK is generated first when the repository has WithIDGenerator.
+func (r *Repository) Save(ctx context.Context, entity any, opts ...WriteOpt) error {
+	if err := r.assignID(entity); err != nil {
+		return err
+	}
+	if err := r.beforeSave(ctx, entity); err != nil {
+		return err
+	}
+	if err := r.save(ctx, entity, opts); err != nil {
+		return err
+	}
+	return r.afterSave(ctx, entity)
+}
+
+func (r *Repository) save(ctx context.Context, entity any, opts []WriteOpt) error {
+	key, err := r.Key(entity)
+	if err != nil {
+		return err
+	}
+	if vf, ok := versionField(reflect.TypeOf(entity)); ok {
+		return r.saveVersioned(ctx, key, entity, vf, opts)
+	}
+	if r.json {
+		doc, err := jsonDoc(entity)
+		if err != nil {
+			return err
+		}
+		return writeDoc(ctx, r.exec, key, entity, newWriteCfg(opts), true,
+			len(key)+len(doc), []interface{}{"JSON.SET", key, "$", string(doc)})
+	}
+	vals, err := structToMap(entity)
+	if err != nil {
+		return err
+	}
+	return writeDoc(ctx, r.exec, key, entity, newWriteCfg(opts), true,
+		payloadSize(key, vals), hset(key, vals))
+}
+
+// saveVersioned is Save for models with a VERSION field: the write only
+// happens if the stored version still equals the entity's, and the entity
+// carries the incremented version afterwards.  See version.go.
+func (r *Repository) saveVersioned(ctx context.Context, key string, entity any, vf scan.Field, opts []WriteOpt) error {
+	cur, restore, err := bumpVersion(entity, vf)
+	if err != nil {
+		return err
+	}
+	var (
+		vals map[string]any
+		doc  []byte
+		size int
+	)
+	if r.json {
+		doc, err = jsonDoc(entity)
+		size = len(key) + len(doc)
+	} else {
+		vals, err = structToMap(entity)
+		size = payloadSize(key, vals)
+	}
+	if err == nil {
+		err = writeDoc(ctx, r.exec, key, entity, newWriteCfg(opts), true,
+			size, r.versionCmd(key, vf, cur, vals, doc))
+	}
+	if err != nil {
+		restore()
+	}
+	return staleVersion(key, err)
+}
+
+// Get loads the entity with primary key id into dst (a pointer to a tagged
+// struct).  It returns ErrNotFound when there is no such hash.
+func (r *Repository) Get(ctx context.Context, id any, dst any) error {
+	rv := reflect.ValueOf(dst)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("repository: Get needs a non-nil pointer, got %T", dst)
+	}
+	key, err := r.idKey(id, rv.Type().Elem())
+	if err != nil {
+		return err
+	}
+	args := []interface{}{"HGETALL", key}
+	if r.json {
+		args = []interface{}{"JSON.GET", key, "$"}
+	}
+	raw, err := r.exec.Do(ctx, args...)
+	if errors.Is(err, redis.Nil) {
+		return fmt.Errorf("repository: %s: %w", key, ErrNotFound)
+	}
+	if err != nil {
+		return driver.Wrap(args, err)
+	}
+	var fields map[string]string
+	if r.json {
+		fields, err = jsonFields(fmt.Sprint(raw), rv.Type().Elem())
+	} else {
+		fields, err = scan.HashFields(raw)
+	}
+	if err != nil {
+		return err
+	}
+	if len(fields) == 0 {
+		return fmt.Errorf("repository: %s: %w", key, ErrNotFound)
+	}
+	if err := scan.Into(dst, fields); err != nil {
+		return err
+	}
+	return r.afterLoad(ctx, dst)
+}
+
+// Delete removes an entity, given either the entity itself or its primary
+// key (the latter needs WithPrefix).  Deleting a missing entity is not an
+// error.  Soft-deleting models are stamped instead; deleted by primary key
+// that is known only from the ForModel type, so a New repository always
+// removes the document – pass the entity there to soft-delete.
+func (r *Repository) Delete(ctx context.Context, idOrEntity any, opts ...WriteOpt) error {
+	key, err := r.idKey(idOrEntity, nil)
+	if err != nil {
+		return err
+	}
+	var entity any
+	if rv := reflect.Indirect(reflect.ValueOf(idOrEntity)); rv.Kind() == reflect.Struct {
+		entity = idOrEntity
+	}
+	if err := r.beforeDelete(ctx, key, entity); err != nil {
+		return err
+	}
+	var model reflect.Type
+	if entity != nil {
+		model = reflect.Indirect(reflect.ValueOf(entity)).Type()
+	}
+	if f, ok := r.softField(model); ok {
+		return r.softDelete(ctx, key, model, f, opts)
+	}
+	return writeDoc(ctx, r.exec, key, nil, newWriteCfg(opts), false,
+		len(key), []interface{}{"DEL", key})
+}
+
+// Exists reports whether the entity is stored; like Delete it takes the
+// entity or its primary key.
+func (r *Repository) Exists(ctx context.Context, idOrEntity any) (bool, error) {
+	key, err := r.idKey(idOrEntity, nil)
+	if err != nil {
+		return false, err
+	}
+	args := []interface{}{"EXISTS", key}
+	raw, err := r.exec.Do(ctx, args...)
+	if err != nil {
+		return false, driver.Wrap(args, err)
+	}
+	n, _ := raw.(int64)
+	return n == 1, nil
+}
+
+// idKey resolves an entity or a bare primary key to its Redis key.  model,
+// when known, names the PK field a key template needs.
+func (r *Repository) idKey(id any, model reflect.Type) (string, error) {
+	rv := reflect.ValueOf(id)
+	if rv.Kind() == reflect.Pointer {
+		rv = rv.Elem()
+	}
+	if rv.Kind() == reflect.Struct {
+		return r.Key(id)
+	}
+	if r.err != nil {
+		return "", r.err
+	}
+	if r.keyFn == nil && r.prefix != "" {
+		return r.prefix + fmt.Sprint(id), nil
+	}
+	if model == nil {
+		return "", fmt.Errorf("repository: addressing by ID needs WithPrefix; pass the entity instead")
+	}
+	pk, ok := pkField(model)
+	if !ok {
+		return "", fmt.Errorf("repository: %s has no PK field", model)
+	}
+	return r.Key(map[string]any{pk: id, "pk": id}) // "pk" serves {pk} templates
+}
+
+// pkField returns the redisorm name of the field tagged PK.
+func pkField(rt reflect.Type) (string, bool) {
+	for _, f := range scan.Fields(rt) {
+		if hasAttr(f.Attrs, "PK") {
+			return f.Name, true
+		}
+	}
+	return "", false
+}
+
+// pkValue returns the primary key of a tagged struct, or of a map keyed by
+// field name (how by-ID lookups reach the key scheme).
+func pkValue(record any) (string, error) {
+	if m, ok := record.(map[string]any); ok && len(m) == 1 {
+		for _, v := range m {
+			return fmt.Sprint(v), nil
+		}
+	}
+	pk, ok := pkField(reflect.TypeOf(record))
+	if !ok {
+		return "", fmt.Errorf("repository: %T has no PK field", record)
+	}
+	vals, err := structToMap(record)
+	if err != nil {
+		return "", err
+	}
+	s := fmt.Sprint(vals[pk])
+	if s == "" {
+		return "", fmt.Errorf("repository: %T has an empty primary key", record)
+	}
+	return s, nil
+}
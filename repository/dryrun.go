@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	q "github.com/manojoshi/redisorm/query"
+)
+
+// Command is a compiled FT.SEARCH / FT.AGGREGATE captured by DryRun.
+type Command struct {
+	Args []interface{}
+}
+
+// Query returns the query string of the command.
+func (c Command) Query() string {
+	if len(c.Args) < 3 {
+		return ""
+	}
+	return fmt.Sprint(c.Args[2])
+}
+
+// String renders the command as it would be typed into redis-cli, quoting
+// arguments that need it – a stable form for logs and golden files.
+func (c Command) String() string {
+	parts := make([]string, len(c.Args))
+	for i, a := range c.Args {
+		s := fmt.Sprint(a)
+		if s == "" || strings.ContainsAny(s, " \t\n\"'\\") {
+			s = strconv.Quote(s)
+		}
+		parts[i] = s
+	}
+	return strings.Join(parts, " ")
+}
+
+// DryRun makes Search, SearchWithTotal, SearchIter, SearchPage, Find,
+// FindIter, Aggregate and AggregateStream store the command they would
+// send in cmd instead of sending it.  The call then returns no results:
+//
+//	var cmd repository.Command
+//	_, _ = repo.Search(ctx, where, repository.SortAsc("qty"), repository.DryRun(&cmd))
+//	fmt.Println(cmd) // FT.SEARCH order_idx "(@status:{PENDING})" SORTBY qty ASC LIMIT 0 10000
+func DryRun(cmd *Command) Opt {
+	exec := dryRunExec{cmd}
+	return optFunc{
+		search: func(b *q.SearchBuilder) { b.Using(exec) },
+		agg:    func(b *q.AggregateBuilder) { b.Using(exec) },
+		load:   func(c *loadCfg) { c.dryRun = cmd },
+	}
+}
+
+// dryRunExec records the command and answers with an empty result.
+type dryRunExec struct{ cmd *Command }
+
+func (e dryRunExec) Do(_ context.Context, args ...interface{}) (any, error) {
+	e.cmd.Args = args
+	empty := []interface{}{int64(0)}
+	for _, a := range args {
+		if a == "WITHCURSOR" {
+			return []interface{}{empty, int64(0)}, nil
+		}
+	}
+	return empty, nil
+}
@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/manojoshi/redisorm/driver"
+)
+
+// ErrNoRawClient is returned by Repo calls that need the raw client
+// (LiveSearch) when WithConn got a nil one.
+var ErrNoRawClient = errors.New("repository: raw Redis client not configured")
+
+// ErrTooManyResults is returned by One when more than one document matches.
+var ErrTooManyResults = errors.New("repository: more than one result")
+
+// ErrStaleVersion is returned by Save when the entity's VERSION field no
+// longer matches the stored document: another writer saved it first.
+var ErrStaleVersion = errors.New("repository: stale version")
+
+// ErrNoModel is returned by Repository.EnsureIndex when the repository was
+// built with New rather than ForModel and so has no model to index.
+var ErrNoModel = errors.New("repository: no model bound (use ForModel)")
+
+// Re-exported from driver; errors returned by repository calls are
+// classified, so errors.Is(err, repository.ErrIndexNotFound) works.
+var (
+	ErrNotFound      = driver.ErrNotFound
+	ErrIndexNotFound = driver.ErrIndexNotFound
+	ErrTimeout       = driver.ErrTimeout
+	ErrSyntax        = driver.ErrSyntax
+	ErrCursorExpired = driver.ErrCursorExpired
+)
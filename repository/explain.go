@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"context"
+
+	q "github.com/manojoshi/redisorm/query"
+)
+
+// Explain returns the execution plan RediSearch builds for where, as Search
+// would send it with opts (FT.EXPLAINCLI); nothing is executed.
+//
+//	plan, err := repo.Explain(ctx, q.And(q.Eq("status", "PENDING"), q.Gt("qty", 5)))
+//	fmt.Print(plan) // INTERSECT { TAG:@status { PENDING } NUMERIC {5 < @qty <= inf} }
+func (r *Repository) Explain(ctx context.Context, where q.Expr, opts ...Opt) (*q.PlanNode, error) {
+	return r.searchFor(where, opts).Explain(ctx)
+}
+
+// Profile runs where as Search would with opts, under FT.PROFILE, and
+// returns the iterator tree and result-processor timings.
+func (r *Repository) Profile(ctx context.Context, where q.Expr, opts ...Opt) (*q.Profile, error) {
+	return r.searchFor(where, opts).Profile(ctx)
+}
+
+// ProfileAggregate is Profile for the FT.AGGREGATE Aggregate would send.
+func (r *Repository) ProfileAggregate(ctx context.Context, where q.Expr, opts ...Opt) (*q.Profile, error) {
+	ab := r.newAggregate(r.scoped(where, nil, opts)).
+		Using(r.exec)
+	for _, opt := range opts {
+		opt.applyAgg(ab)
+	}
+	return ab.Profile(ctx)
+}
+
+// searchFor is the builder Search uses for where and opts.
+func (r *Repository) searchFor(where q.Expr, opts []Opt) *q.SearchBuilder {
+	sb := r.newSearch(r.scoped(where, nil, opts)).
+		Using(r.exec)
+	for _, opt := range opts {
+		opt.applySearch(sb)
+	}
+	return sb
+}
@@ -0,0 +1,222 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/manojoshi/redisorm/driver"
+	q "github.com/manojoshi/redisorm/query"
+	"github.com/manojoshi/redisorm/scan"
+)
+
+// loadCfg collects the per-call options that act outside the query
+// builders: Find's decode phase and Search's spell-corrected retry.
+type loadCfg struct {
+	preload     []string
+	corrections *Corrections // SpellCorrect destination
+	skipMissing bool         // MGet: drop IDs with no document
+	unscoped    bool         // include soft-deleted documents
+	dryRun      *Command     // DryRun: record instead of sending
+}
+
+// Preload eagerly loads the referenced document behind a REF field after a
+// Find, with one pipelined HGETALL batch for the whole page (no N+1):
+//
+//	type Order struct {
+//	    CustomerID string    `redisorm:"@customer_id,TAG,REF=customer:"`
+//	    Customer   *Customer // filled by Preload("Customer")
+//	}
+//	orders, err := repository.Find[Order](ctx, repo, where, repository.Preload("Customer"))
+//
+// The reference field is found by the usual <Name>ID convention and its REF
+// attribute holds the key prefix of the referenced documents.
+func Preload(fields ...string) Opt {
+	return optFunc{
+		load: func(c *loadCfg) { c.preload = append(c.preload, fields...) },
+	}
+}
+
+// Find runs a FT.SEARCH on the repository's index and decodes the hits into
+// T (a struct tagged with redisorm or map[string]string).
+func Find[T any](ctx context.Context, r *Repository, where q.Expr, opts ...Opt) ([]T, error) {
+	model := reflect.TypeOf((*T)(nil)).Elem()
+	hits, cfg, err := r.searchHits(ctx, r.scoped(where, model, opts), opts)
+	if err == nil && r.json {
+		err = expandJSON(hits, model)
+	}
+	if err != nil {
+		return nil, err
+	}
+	out, err := scan.Docs[T](hits)
+	if err != nil {
+		return nil, err
+	}
+	if err := loaded(ctx, r, out, cfg); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// First returns the first hit of Find, in the order set by a SortAsc /
+// SortDesc option (relevance otherwise), or ErrNotFound when nothing
+// matches.
+//
+//	latest, err := repository.First[Order](ctx, repo, q.Eq("customer_id", id),
+//	    repository.SortDesc("created_ts"))
+func First[T any](ctx context.Context, r *Repository, where q.Expr, opts ...Opt) (T, error) {
+	var zero T
+	out, err := Find[T](ctx, r, where, append(opts[:len(opts):len(opts)], Limit(0, 1))...)
+	if err != nil {
+		return zero, err
+	}
+	if len(out) == 0 {
+		return zero, fmt.Errorf("repository: %s: %w", r.index, ErrNotFound)
+	}
+	return out[0], nil
+}
+
+// One returns the single document matching where: ErrNotFound when there
+// is none, ErrTooManyResults when there is more than one – for lookups by
+// a field that is unique by convention (email, external ID).
+func One[T any](ctx context.Context, r *Repository, where q.Expr, opts ...Opt) (T, error) {
+	var zero T
+	out, err := Find[T](ctx, r, where, append(opts[:len(opts):len(opts)], Limit(0, 2))...)
+	switch {
+	case err != nil:
+		return zero, err
+	case len(out) == 0:
+		return zero, fmt.Errorf("repository: %s: %w", r.index, ErrNotFound)
+	case len(out) > 1:
+		return zero, fmt.Errorf("repository: %s: %w", r.index, ErrTooManyResults)
+	}
+	return out[0], nil
+}
+
+// FindPoly is Find for heterogeneous indexes: every hit is decoded into the
+// type its discriminator selects in types (see scan.TypeMap).  Preload is
+// not applied, since it is defined per model.
+func FindPoly(ctx context.Context, r *Repository, types *scan.TypeMap, where q.Expr, opts ...Opt) ([]any, error) {
+	hits, _, err := r.searchHits(ctx, r.scoped(where, nil, opts), opts)
+	if err == nil && r.json {
+		err = expandJSON(hits, nil)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return types.DecodeHits(hits)
+}
+
+// searchHits runs the search behind Find / FindPoly.
+func (r *Repository) searchHits(ctx context.Context, where q.Expr, opts []Opt) ([]scan.Hit, *loadCfg, error) {
+	sb := r.newSearch(where).Using(r.exec)
+	cfg := &loadCfg{}
+	for _, o := range opts {
+		o.applySearch(sb)
+		o.applyLoad(cfg)
+	}
+	exec := r.exec
+	if cfg.dryRun != nil {
+		exec = dryRunExec{cfg.dryRun}
+	}
+	hits, err := runHits(ctx, exec, sb)
+	if err != nil || len(hits) > 0 {
+		return hits, cfg, err
+	}
+	fixed, err := r.corrected(ctx, where, cfg)
+	if err != nil || fixed == nil {
+		return hits, cfg, err
+	}
+	hits, err = runHits(ctx, exec, sb.Where(r.rewritten(fixed)))
+	return hits, cfg, err
+}
+
+func runHits(ctx context.Context, exec driver.Executor, sb *q.SearchBuilder) ([]scan.Hit, error) {
+	args, err := sb.RawArgs()
+	if err != nil {
+		return nil, err
+	}
+	raw, err := exec.Do(ctx, args...)
+	if err != nil {
+		return nil, driver.Wrap(args, err)
+	}
+	return sb.DecodeHits(raw)
+}
+
+// preload fills the field called name on every element of docs.
+func preload[T any](ctx context.Context, exec driver.Executor, docs []T, name string) error {
+	rt := reflect.TypeOf((*T)(nil)).Elem()
+	if rt.Kind() != reflect.Struct {
+		return fmt.Errorf("repository: Preload(%q) needs a struct model, got %s", name, rt)
+	}
+	target, ok := rt.FieldByName(name)
+	if !ok {
+		return fmt.Errorf("repository: Preload(%q): %s has no such field", name, rt)
+	}
+	ref, ok := rt.FieldByName(name + "ID")
+	if !ok {
+		return fmt.Errorf("repository: Preload(%q): %s has no %sID field", name, rt, name)
+	}
+	prefix, ok := refPrefix(ref.Tag.Get("redisorm"))
+	if !ok {
+		return fmt.Errorf("repository: Preload(%q): %s.%sID has no REF attribute", name, rt, name)
+	}
+
+	// one HGETALL per distinct id
+	slot := make(map[string]int)
+	var cmds [][]interface{}
+	for i := range docs {
+		id := fmt.Sprint(reflect.ValueOf(&docs[i]).Elem().FieldByIndex(ref.Index).Interface())
+		if _, seen := slot[id]; id == "" || seen {
+			continue
+		}
+		slot[id] = len(cmds)
+		cmds = append(cmds, []interface{}{"HGETALL", prefix + id})
+	}
+	if len(cmds) == 0 {
+		return nil
+	}
+	replies, err := driver.DoBatch(ctx, exec, cmds)
+	if err != nil {
+		return err
+	}
+
+	for i := range docs {
+		doc := reflect.ValueOf(&docs[i]).Elem()
+		id := fmt.Sprint(doc.FieldByIndex(ref.Index).Interface())
+		n, ok := slot[id]
+		if !ok {
+			continue
+		}
+		if e, isErr := replies[n].(error); isErr {
+			return fmt.Errorf("repository: Preload(%q) %s%s: %w", name, prefix, id, e)
+		}
+		kv, err := scan.HashFields(replies[n])
+		if err != nil {
+			return err
+		}
+		if len(kv) == 0 {
+			continue // dangling reference: leave zero value
+		}
+		dst := doc.FieldByIndex(target.Index)
+		if dst.Kind() == reflect.Pointer {
+			dst.Set(reflect.New(dst.Type().Elem()))
+			dst = dst.Elem()
+		}
+		if err := scan.Into(dst.Addr().Interface(), kv); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// refPrefix extracts the key prefix from a "REF=customer:" tag attribute.
+func refPrefix(tag string) (string, bool) {
+	for _, a := range strings.Split(tag, ",")[1:] {
+		if k, v, ok := strings.Cut(a, "="); ok && strings.EqualFold(k, "REF") {
+			return v, true
+		}
+	}
+	return "", false
+}
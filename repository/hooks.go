@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// Lifecycle hooks.  A model opts in by implementing any of these on its
+// pointer type; the repository calls them around persistence and decoding:
+//
+//	func (o *Order) BeforeSave(ctx context.Context) error {
+//	    if o.Qty <= 0 {
+//	        return errors.New("order: qty must be positive")
+//	    }
+//	    o.UpdatedAt = time.Now()
+//	    return nil
+//	}
+//
+// An error from a Before hook aborts the operation; from an After hook it
+// is returned after the write or decode has happened.
+type (
+	// BeforeSaver runs in Save / Create once the ID is assigned, before the
+	// document is encoded.
+	BeforeSaver interface {
+		BeforeSave(ctx context.Context) error
+	}
+	// AfterSaver runs after a successful Save / Create.
+	AfterSaver interface {
+		AfterSave(ctx context.Context) error
+	}
+	// BeforeDeleter runs in Delete when it is given the entity (not an ID).
+	BeforeDeleter interface {
+		BeforeDelete(ctx context.Context) error
+	}
+	// AfterLoader runs on every entity decoded by Get, Find, First, One and
+	// MGet.
+	AfterLoader interface {
+		AfterLoad(ctx context.Context) error
+	}
+)
+
+// Hooks are repository-wide middleware, run for every entity of any model
+// after the entity's own hook of the same kind.  Nil fields are skipped.
+// Delete passes the key, and entity only when it was given one.
+type Hooks struct {
+	BeforeSave   func(ctx context.Context, entity any) error
+	AfterSave    func(ctx context.Context, entity any) error
+	BeforeDelete func(ctx context.Context, key string, entity any) error
+	AfterLoad    func(ctx context.Context, entity any) error
+}
+
+// WithHooks registers repository-wide hooks – audit stamping, validation,
+// metrics.  It may be given several times; hooks run in registration order.
+func WithHooks(h Hooks) Option {
+	return func(r *Repository) { r.hooks = append(r.hooks, h) }
+}
+
+func (r *Repository) beforeSave(ctx context.Context, entity any) error {
+	if h, ok := entity.(BeforeSaver); ok {
+		if err := h.BeforeSave(ctx); err != nil {
+			return fmt.Errorf("repository: BeforeSave: %w", err)
+		}
+	}
+	for _, h := range r.hooks {
+		if h.BeforeSave != nil {
+			if err := h.BeforeSave(ctx, entity); err != nil {
+				return fmt.Errorf("repository: BeforeSave: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+func (r *Repository) afterSave(ctx context.Context, entity any) error {
+	if h, ok := entity.(AfterSaver); ok {
+		if err := h.AfterSave(ctx); err != nil {
+			return fmt.Errorf("repository: AfterSave: %w", err)
+		}
+	}
+	for _, h := range r.hooks {
+		if h.AfterSave != nil {
+			if err := h.AfterSave(ctx, entity); err != nil {
+				return fmt.Errorf("repository: AfterSave: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// beforeDelete runs the delete hooks; entity is nil for deletes by ID.
+func (r *Repository) beforeDelete(ctx context.Context, key string, entity any) error {
+	if h, ok := entity.(BeforeDeleter); ok {
+		if err := h.BeforeDelete(ctx); err != nil {
+			return fmt.Errorf("repository: BeforeDelete: %w", err)
+		}
+	}
+	for _, h := range r.hooks {
+		if h.BeforeDelete != nil {
+			if err := h.BeforeDelete(ctx, key, entity); err != nil {
+				return fmt.Errorf("repository: BeforeDelete: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// afterLoad runs the load hooks on entity, a pointer to a decoded value.
+func (r *Repository) afterLoad(ctx context.Context, entity any) error {
+	if h, ok := entity.(AfterLoader); ok {
+		if err := h.AfterLoad(ctx); err != nil {
+			return fmt.Errorf("repository: AfterLoad: %w", err)
+		}
+	}
+	for _, h := range r.hooks {
+		if h.AfterLoad != nil {
+			if err := h.AfterLoad(ctx, entity); err != nil {
+				return fmt.Errorf("repository: AfterLoad: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// loaded finishes a decoded page for Find and MGet: Preload, then the
+// AfterLoad hooks on each element.
+func loaded[T any](ctx context.Context, r *Repository, out []T, cfg *loadCfg) error {
+	for _, name := range cfg.preload {
+		if err := preload(ctx, r.exec, out, name); err != nil {
+			return err
+		}
+	}
+	for i := range out {
+		e := any(&out[i])
+		if reflect.ValueOf(out[i]).Kind() == reflect.Pointer { // Find[*Order]
+			e = out[i]
+		}
+		if err := r.afterLoad(ctx, e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/manojoshi/redisorm/scan"
+)
+
+// Generator produces a new primary key for an entity saved without one.
+// ULID and UUIDv7 are built in; any func will do (snowflake IDs, a Redis
+// INCR counter, …).
+type Generator func() (string, error)
+
+// WithIDGenerator makes Save fill an empty string PK field with gen's
+// result before writing, so callers need no key scheme of their own:
+//
+//	repo := repository.New("order_idx", conn,
+//	    repository.WithPrefix("order:"),
+//	    repository.WithIDGenerator(repository.ULID))
+//	id, err := repo.Create(ctx, &Order{Status: "NEW"}) // order:01J9…
+func WithIDGenerator(gen Generator) Option {
+	return func(r *Repository) { r.idGen = gen }
+}
+
+// Create is Save for new entities: it assigns a primary key through the
+// repository's Generator when the PK field is empty (Save does too) and
+// returns the entity's ID.  entity must be a pointer so the ID can be
+// stored in it.
+func (r *Repository) Create(ctx context.Context, entity any, opts ...WriteOpt) (string, error) {
+	if err := r.Save(ctx, entity, opts...); err != nil {
+		return "", err
+	}
+	return pkValue(entity)
+}
+
+// assignID fills the empty PK field of entity from the generator, if the
+// repository has one.
+func (r *Repository) assignID(entity any) error {
+	if r.idGen == nil {
+		return nil
+	}
+	rv := reflect.ValueOf(entity)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return nil
+	}
+	for _, f := range scan.Fields(rv.Elem().Type()) {
+		if !hasAttr(f.Attrs, "PK") {
+			continue
+		}
+		fv := rv.Elem().FieldByIndex(f.Index)
+		if fv.Kind() != reflect.String || fv.String() != "" {
+			return nil
+		}
+		id, err := r.idGen()
+		if err != nil {
+			return fmt.Errorf("repository: generate ID: %w", err)
+		}
+		fv.SetString(id)
+		return nil
+	}
+	return nil
+}
+
+// ULID returns a new ULID: 48 bits of millisecond time and 80 random bits
+// in Crockford base32, 26 characters that sort by creation time.
+func ULID() (string, error) {
+	var b [16]byte
+	putMillis(b[:6], time.Now())
+	if _, err := rand.Read(b[6:]); err != nil {
+		return "", err
+	}
+	const alphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+	hi, lo := binary.BigEndian.Uint64(b[:8]), binary.BigEndian.Uint64(b[8:])
+	var out [26]byte
+	for i := 25; i >= 0; i-- { // 128 bits, 5 at a time from the low end
+		out[i] = alphabet[lo&31]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(out[:]), nil
+}
+
+// UUIDv7 returns a new RFC 9562 version 7 UUID: time-ordered, random
+// otherwise, in the usual 8-4-4-4-12 hex form.
+func UUIDv7() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[6:]); err != nil {
+		return "", err
+	}
+	putMillis(b[:6], time.Now())
+	b[6] = b[6]&0x0f | 0x70 // version 7
+	b[8] = b[8]&0x3f | 0x80 // RFC 4122 variant
+	var out [36]byte
+	hex.Encode(out[0:8], b[0:4])
+	hex.Encode(out[9:13], b[4:6])
+	hex.Encode(out[14:18], b[6:8])
+	hex.Encode(out[19:23], b[8:10])
+	hex.Encode(out[24:], b[10:])
+	out[8], out[13], out[18], out[23] = '-', '-', '-', '-'
+	return string(out[:]), nil
+}
+
+// putMillis writes t as 48-bit big-endian unix milliseconds into b[:6].
+func putMillis(b []byte, t time.Time) {
+	ms := uint64(t.UnixMilli())
+	for i := 5; i >= 0; i-- {
+		b[i] = byte(ms)
+		ms >>= 8
+	}
+}
@@ -0,0 +1,234 @@
+package repository
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/manojoshi/redisorm/index"
+	"github.com/manojoshi/redisorm/scan"
+)
+
+// AsJSON stores entities as RedisJSON documents instead of hashes: Save
+// writes JSON.SET key $, Get reads JSON.GET, and search results – the
+// single "$" blob FT.SEARCH returns for ON JSON indexes – are decoded back
+// into the tagged fields.  Create the index with index.OnJSON(); its
+// schema then uses "$.field AS field" paths (see index.BuildJSONSchema).
+func AsJSON() Option {
+	return func(r *Repository) { r.json = true }
+}
+
+// jsonDoc encodes entity as the JSON document AsJSON stores: keys are the
+// redisorm field names, numeric codecs (time.Time, big.Int) become JSON
+// numbers so NUMERIC fields index, []string stays an array and nested
+// fragments become nested objects.
+func jsonDoc(entity any) ([]byte, error) {
+	rv := reflect.ValueOf(entity)
+	if rv.Kind() == reflect.Pointer {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return json.Marshal(entity)
+	}
+	out := map[string]any{}
+	for _, sf := range scan.Fields(rv.Type()) {
+		if scan.MetaField(sf.Name) {
+			continue
+		}
+		f, fv := sf.Struct, rv.FieldByIndex(sf.Index)
+		obj, name := out, sf.JSON[len(sf.JSON)-1]
+		for _, p := range sf.JSON[:len(sf.JSON)-1] {
+			sub, ok := obj[p].(map[string]any)
+			if !ok {
+				sub = map[string]any{}
+				obj[p] = sub
+			}
+			obj = sub
+		}
+		if hasAttr(sf.Attrs, "VECTOR") { // JSON indexes read vectors as number arrays
+			obj[name] = fv.Interface()
+			continue
+		}
+		if fv.Type() == reflect.TypeOf([]string(nil)) {
+			obj[name] = fv.Interface()
+			if fv.IsNil() {
+				obj[name] = []string{}
+			}
+			continue
+		}
+		codec, ok := scan.CodecForField(f)
+		if !ok {
+			obj[name] = fv.Interface()
+			continue
+		}
+		s, err := codec.Encode(fv)
+		if err != nil {
+			return nil, fmt.Errorf("repository: field %s: %w", f.Name, err)
+		}
+		nc, numeric := codec.(scan.NumericCodec)
+		if _, err := strconv.ParseFloat(s, 64); numeric && err == nil {
+			obj[name] = json.Number(s)
+		} else {
+			obj[name] = s
+		}
+		if numeric && hasAttr(sf.Attrs, "SHADOW") {
+			n, err := nc.Float64(fv)
+			if err != nil {
+				return nil, fmt.Errorf("repository: field %s: %w", f.Name, err)
+			}
+			obj[name+index.ShadowSuffix] = n
+		}
+	}
+	return json.Marshal(out)
+}
+
+// jsonFields flattens a stored JSON document into the field map scan
+// decodes: strings unquoted, numbers and booleans as written, arrays joined
+// by the separator of the matching []string field of model (nil: ","),
+// nested objects flattened to "<key>_<field>" as scan.Fields names them.
+// A JSON.GET $ reply (the document wrapped in an array) is unwrapped.
+func jsonFields(blob string, model reflect.Type) (map[string]string, error) {
+	b := bytes.TrimSpace([]byte(blob))
+	if len(b) > 0 && b[0] == '[' {
+		var docs []json.RawMessage
+		if err := json.Unmarshal(b, &docs); err != nil {
+			return nil, fmt.Errorf("repository: JSON document: %w", err)
+		}
+		if len(docs) == 0 {
+			return map[string]string{}, nil
+		}
+		b = docs[0]
+	}
+	out := map[string]string{}
+	return out, flattenJSON(out, b, "", listSeparators(model), vectorFields(model))
+}
+
+func flattenJSON(out map[string]string, b []byte, prefix string, seps map[string]string, vecs map[string]scan.Field) error {
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(b, &doc); err != nil {
+		return fmt.Errorf("repository: JSON document: %w", err)
+	}
+	for k, raw := range doc {
+		k = prefix + k
+		switch raw[0] {
+		case 'n': // null
+		case '"':
+			var s string
+			if err := json.Unmarshal(raw, &s); err != nil {
+				return fmt.Errorf("repository: JSON field %s: %w", k, err)
+			}
+			out[k] = s
+		case '[':
+			if f, ok := vecs[k]; ok {
+				blob, err := vectorBlob(raw, f)
+				if err != nil {
+					return fmt.Errorf("repository: JSON field %s: %w", k, err)
+				}
+				out[k] = blob
+				continue
+			}
+			var elems []any
+			if err := json.Unmarshal(raw, &elems); err != nil {
+				return fmt.Errorf("repository: JSON field %s: %w", k, err)
+			}
+			ss := make([]string, len(elems))
+			for i, e := range elems {
+				ss[i] = fmt.Sprint(e)
+			}
+			sep := seps[k]
+			if sep == "" {
+				sep = ","
+			}
+			out[k] = strings.Join(ss, sep)
+		case '{':
+			if err := flattenJSON(out, raw, k+"_", seps, vecs); err != nil {
+				return err
+			}
+		default: // numbers, booleans
+			out[k] = string(raw)
+		}
+	}
+	return nil
+}
+
+// listSeparators maps the []string fields of model to their TAG separator.
+func listSeparators(model reflect.Type) map[string]string {
+	if model == nil {
+		return nil
+	}
+	out := map[string]string{}
+	for _, f := range scan.Fields(model) {
+		if f.Struct.Type == reflect.TypeOf([]string(nil)) {
+			out[f.Name] = scan.TagSeparator(f.Tag)
+		}
+	}
+	return out
+}
+
+// vectorFields maps the VECTOR fields of model by name.
+func vectorFields(model reflect.Type) map[string]scan.Field {
+	if model == nil {
+		return nil
+	}
+	out := map[string]scan.Field{}
+	for _, f := range scan.Fields(model) {
+		if hasAttr(f.Attrs, "VECTOR") {
+			out[f.Name] = f
+		}
+	}
+	return out
+}
+
+// vectorBlob re-encodes a JSON number array in the blob form the VECTOR
+// field f decodes from (see scan.CodecForField).
+func vectorBlob(raw json.RawMessage, f scan.Field) (string, error) {
+	v := reflect.New(f.Struct.Type)
+	if err := json.Unmarshal(raw, v.Interface()); err != nil {
+		return "", err
+	}
+	codec, ok := scan.CodecForField(f.Struct)
+	if !ok {
+		return string(raw), nil
+	}
+	return codec.Encode(v.Elem())
+}
+
+// expandJSON replaces the "$" blob of ON JSON hits with the document's
+// fields; hits fetched with RETURN already carry them and are left alone.
+func expandJSON(hits []scan.Hit, model reflect.Type) error {
+	for i := range hits {
+		blob, ok := hits[i].Fields["$"]
+		if !ok {
+			continue
+		}
+		fields, err := jsonFields(blob, model)
+		if err != nil {
+			return err
+		}
+		hits[i].Fields = fields
+	}
+	return nil
+}
+
+// expandRows is expandJSON for decoded rows of an AsJSON repository, in
+// place, so getters and page cursors see the document fields.
+func (r *Repository) expandRows(rows []map[string]string) error {
+	if !r.json {
+		return nil
+	}
+	for i, row := range rows {
+		blob, ok := row["$"]
+		if !ok {
+			continue
+		}
+		fields, err := jsonFields(blob, nil)
+		if err != nil {
+			return err
+		}
+		rows[i] = fields
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// KeyFunc derives the Redis key of a record.  The record is either a tagged
+// struct or a map[string]any keyed by redisorm field names — the latter is
+// how by-ID lookups call it, with just the PK filled in.
+type KeyFunc func(record any) (string, error)
+
+// KeyTemplate compiles a key pattern such as
+//
+//	"order:{warehouse_id}:{order_id}"
+//
+// into a KeyFunc.  {field} is replaced by the record's value for that field.
+// {{field}} additionally wraps the value in a Redis Cluster hash tag, so
+// "order:{{customer_id}}:{order_id}" keeps all of a customer's orders in one
+// slot.  {pk} stands for the field tagged PK, whatever its name, unless the
+// model has a field named pk.  Every referenced field must be present and
+// non-empty.
+func KeyTemplate(tmpl string) (KeyFunc, error) {
+	type part struct {
+		lit     string
+		field   string
+		hashTag bool
+	}
+	var parts []part
+	rest := tmpl
+	for rest != "" {
+		open := strings.IndexByte(rest, '{')
+		if open < 0 {
+			parts = append(parts, part{lit: rest})
+			break
+		}
+		if open > 0 {
+			parts = append(parts, part{lit: rest[:open]})
+		}
+		rest = rest[open:]
+		p := part{}
+		closing := "}"
+		if strings.HasPrefix(rest, "{{") {
+			p.hashTag, closing, rest = true, "}}", rest[2:]
+		} else {
+			rest = rest[1:]
+		}
+		end := strings.Index(rest, closing)
+		if end <= 0 {
+			return nil, fmt.Errorf("repository: key template %q: unterminated or empty placeholder", tmpl)
+		}
+		p.field = strings.TrimPrefix(rest[:end], "@")
+		parts = append(parts, p)
+		rest = rest[end+len(closing):]
+	}
+
+	return func(record any) (string, error) {
+		fields, err := structToMap(record)
+		if err != nil {
+			return "", err
+		}
+		var sb strings.Builder
+		for _, p := range parts {
+			if p.field == "" {
+				sb.WriteString(p.lit)
+				continue
+			}
+			v, ok := fields[p.field]
+			if !ok && p.field == "pk" {
+				if name, found := pkField(reflect.TypeOf(record)); found {
+					v, ok = fields[name]
+				}
+			}
+			s := fmt.Sprint(v)
+			if !ok || s == "" {
+				return "", fmt.Errorf("repository: key template %q needs field %q", tmpl, p.field)
+			}
+			if p.hashTag {
+				s = "{" + s + "}"
+			}
+			sb.WriteString(s)
+		}
+		return sb.String(), nil
+	}, nil
+}
+
+// PrefixKey is the classic prefix + primary key scheme ("order:" + order_id).
+func PrefixKey(prefix, pkField string) KeyFunc {
+	kf, _ := KeyTemplate(prefix + "{" + strings.TrimPrefix(pkField, "@") + "}")
+	return kf
+}
@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Limiter is a token-bucket write limiter shared by every write that carries
+// it (see Throttle), so a batch importer can be pointed at a shared
+// production Redis without a separate throttling layer.  Both budgets allow
+// bursts of one second's worth.  A Limiter is safe for concurrent use.
+type Limiter struct {
+	mu          sync.Mutex
+	ops, bytes  bucket
+	opsPerSec   float64
+	bytesPerSec float64
+}
+
+// NewLimiter limits writes to opsPerSec and payloads to bytesPerSec; zero
+// disables the corresponding budget.
+func NewLimiter(opsPerSec, bytesPerSec float64) *Limiter {
+	now := time.Now()
+	return &Limiter{
+		ops:         bucket{tokens: opsPerSec, last: now},
+		bytes:       bucket{tokens: bytesPerSec, last: now},
+		opsPerSec:   opsPerSec,
+		bytesPerSec: bytesPerSec,
+	}
+}
+
+// Wait blocks until ops writes totalling size bytes fit in the budget, or
+// ctx is done.  A single write larger than the byte budget is let through
+// once the bucket is full rather than blocking forever.
+func (l *Limiter) Wait(ctx context.Context, ops, size int) error {
+	for {
+		l.mu.Lock()
+		now := time.Now()
+		d := max(
+			l.ops.reserve(now, float64(ops), l.opsPerSec),
+			l.bytes.reserve(now, float64(size), l.bytesPerSec),
+		)
+		if d == 0 {
+			l.ops.take(float64(ops), l.opsPerSec)
+			l.bytes.take(float64(size), l.bytesPerSec)
+		}
+		l.mu.Unlock()
+		if d == 0 {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(d):
+		}
+	}
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// reserve refills b and returns how long until n tokens are available.
+func (b *bucket) reserve(now time.Time, n, rate float64) time.Duration {
+	if rate <= 0 {
+		return 0
+	}
+	b.tokens = min(b.tokens+now.Sub(b.last).Seconds()*rate, rate)
+	b.last = now
+	need := min(n, rate) // oversized requests wait for a full bucket only
+	if b.tokens >= need {
+		return 0
+	}
+	return time.Duration((need - b.tokens) / rate * float64(time.Second))
+}
+
+func (b *bucket) take(n, rate float64) {
+	if rate > 0 {
+		b.tokens -= n
+	}
+}
+
+// Throttle makes the write wait for l before it is sent.  Pass the same
+// Limiter to every call that shares the budget.
+func Throttle(l *Limiter) WriteOpt { return func(c *writeCfg) { c.limiter = l } }
+
+// payloadSize approximates the bytes a HSET of vals sends.
+func payloadSize(key string, vals map[string]any) int {
+	n := len(key)
+	for k, v := range vals {
+		n += len(k) + len(fmt.Sprint(v))
+	}
+	return n
+}
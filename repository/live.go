@@ -0,0 +1,197 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/manojoshi/redisorm/driver"
+	"github.com/manojoshi/redisorm/index"
+	q "github.com/manojoshi/redisorm/query"
+	"github.com/manojoshi/redisorm/scan"
+)
+
+// ChangeKind says how a live query's result set changed.
+type ChangeKind int
+
+const (
+	Added   ChangeKind = iota + 1 // key started matching
+	Updated                       // key still matches, document changed
+	Removed                       // key no longer matches (or was deleted)
+)
+
+func (k ChangeKind) String() string {
+	switch k {
+	case Added:
+		return "added"
+	case Updated:
+		return "updated"
+	case Removed:
+		return "removed"
+	}
+	return fmt.Sprintf("ChangeKind(%d)", int(k))
+}
+
+// Change is one update pushed by LiveSearch.  Doc is empty for Removed.
+type Change struct {
+	Kind ChangeKind
+	Key  string
+	Doc  map[string]string
+}
+
+// Live is a running live query.  Read Changes until it is closed, then
+// check Err.
+type Live struct {
+	Initial []scan.Hit    // results at subscription time
+	Changes <-chan Change // closed when the query stops
+
+	cancel context.CancelFunc
+	mu     sync.Mutex
+	err    error
+}
+
+// Close stops the live query; Changes is closed shortly after.
+func (l *Live) Close() { l.cancel() }
+
+// Err reports why Changes was closed: nil after Close or context
+// cancellation, otherwise the failure that stopped the query.
+func (l *Live) Err() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.err
+}
+
+// LiveSearch runs where against indexName and then keeps watching it: every
+// write to a key under the index's prefixes triggers a targeted re-query of
+// that key (INKEYS), and the result is pushed as Added / Updated / Removed.
+// Meant for real-time dashboards.
+//
+// It needs the raw client and keyspace notifications enabled on the server
+// (notify-keyspace-events containing "K" and the event classes written,
+// e.g. "Khgx$").  Notifications are fire-and-forget: changes made while the
+// connection is down are missed, so re-subscribe after errors.  Opts apply
+// to the initial query; Limit only bounds that first page.
+func (r *Repo) LiveSearch(ctx context.Context, indexName string, where q.Expr, opts ...Opt) (*Live, error) {
+	if r.raw == nil {
+		return nil, ErrNoRawClient
+	}
+	prefixes, err := index.Prefixes(ctx, r.exec, indexName)
+	if err != nil {
+		return nil, err
+	}
+	if len(prefixes) == 0 {
+		prefixes = []string{""}
+	}
+	channel := fmt.Sprintf("__keyspace@%d__:", r.raw.Options().DB)
+	patterns := make([]string, len(prefixes))
+	for i, p := range prefixes {
+		patterns[i] = channel + p + "*"
+	}
+
+	// subscribe before the initial query so no change falls in between
+	ctx, cancel := context.WithCancel(ctx)
+	sub := r.raw.PSubscribe(ctx, patterns...)
+	if _, err := sub.Receive(ctx); err != nil {
+		cancel()
+		_ = sub.Close()
+		return nil, err
+	}
+
+	hits, err := r.liveQuery(ctx, r.searchBuilder(indexName, where, opts))
+	if err != nil {
+		cancel()
+		_ = sub.Close()
+		return nil, err
+	}
+	changes := make(chan Change, 64)
+	l := &Live{Initial: hits, Changes: changes, cancel: cancel}
+	go r.watch(ctx, l, sub, changes, indexName, where, opts, channel)
+	return l, nil
+}
+
+func (r *Repo) liveQuery(ctx context.Context, sb *q.SearchBuilder) ([]scan.Hit, error) {
+	args, err := sb.RawArgs()
+	if err != nil {
+		return nil, err
+	}
+	raw, err := r.exec.Do(ctx, args...)
+	if err != nil {
+		return nil, driver.Wrap(args, err)
+	}
+	return sb.DecodeHits(raw)
+}
+
+// watch turns keyspace events into Changes until ctx is done.
+func (r *Repo) watch(
+	ctx context.Context,
+	l *Live,
+	sub *redis.PubSub,
+	out chan<- Change,
+	indexName string,
+	where q.Expr,
+	opts []Opt,
+	channel string,
+) {
+	defer close(out)
+	defer sub.Close()
+
+	matching := make(map[string]bool, len(l.Initial))
+	for _, h := range l.Initial {
+		matching[h.Key] = true
+	}
+
+	msgs := sub.Channel()
+	for {
+		var msg *redis.Message
+		select {
+		case <-ctx.Done():
+			return
+		case m, ok := <-msgs:
+			if !ok {
+				if ctx.Err() == nil {
+					l.fail(errors.New("repository: live search subscription closed"))
+				}
+				return
+			}
+			msg = m
+		}
+		key := strings.TrimPrefix(msg.Channel, channel)
+
+		hits, err := r.liveQuery(ctx, r.searchBuilder(indexName, where, opts).InKeys(key).Limit(0, 1))
+		if err != nil {
+			if ctx.Err() == nil {
+				l.fail(err)
+			}
+			return
+		}
+
+		var ch Change
+		switch {
+		case len(hits) > 0 && matching[key]:
+			ch = Change{Kind: Updated, Key: key, Doc: hits[0].Fields}
+		case len(hits) > 0:
+			matching[key] = true
+			ch = Change{Kind: Added, Key: key, Doc: hits[0].Fields}
+		case matching[key]:
+			delete(matching, key)
+			ch = Change{Kind: Removed, Key: key}
+		default:
+			continue // unrelated key under the prefix
+		}
+		select {
+		case out <- ch:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (l *Live) fail(err error) {
+	l.mu.Lock()
+	l.err = err
+	l.mu.Unlock()
+}
@@ -0,0 +1,314 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/manojoshi/redisorm/driver"
+	"github.com/manojoshi/redisorm/internal"
+)
+
+// defaultLoadWorkers is how many LoadBulk chunks are in flight at once.
+const defaultLoadWorkers = 4
+
+// LoadChunk sets how many records LoadBulk writes per pipeline (default
+// 500).
+func LoadChunk(n int) WriteOpt { return func(c *writeCfg) { c.chunk = n } }
+
+// LoadWorkers sets how many LoadBulk pipelines run concurrently (default
+// 4).  Each one holds a pool connection while it is in flight.
+func LoadWorkers(n int) WriteOpt { return func(c *writeCfg) { c.workers = n } }
+
+// LoadReport is the outcome of LoadBulk and MultiUpsert.  Written + Skipped + len(Errors)
+// is the number of records given.
+type LoadReport struct {
+	Written int           // records stored (and replicated / indexed, if asked)
+	Skipped int           // records left out by SkipSeen
+	Errors  []RecordError // failed records, in input order
+}
+
+// RecordError is the failure of one LoadBulk / MultiUpsert record.
+type RecordError struct {
+	Index int    // position in the records slice
+	Key   string // key the record was written to
+	Err   error
+}
+
+func (e RecordError) Error() string {
+	return fmt.Sprintf("repository: record %d (%s): %v", e.Index, e.Key, e.Err)
+}
+
+func (e RecordError) Unwrap() error { return e.Err }
+
+// loadRecord is one record of a LoadBulk / MultiUpsert chunk.
+type loadRecord struct {
+	index int
+	key   string
+	cmd   []interface{} // the write
+	size  int           // payload bytes, for Throttle
+	rec   any
+}
+
+// LoadBulk writes many records; prefix is used if keyFn returns only ID.
+// Repository.MultiUpsert is the same for a Repository, with keys from its
+// key scheme instead of keyFn.
+//
+// Records are split into chunks (LoadChunk) that are each sent as one
+// pipeline, with up to LoadWorkers chunks in flight, so millions of rows
+// load in a few thousand round trips.  A record that cannot be encoded or
+// whose write fails is reported in the LoadReport and does not stop the
+// others; the error is then non-nil too, wrapping the first failure.
+// Throttle is charged per chunk, SkipSeen checks a chunk in one pipeline,
+// WithReplicas WAITs once per chunk, WithTTL makes each chunk one
+// MULTI/EXEC transaction and WaitIndexed waits for the last record of each
+// chunk.
+func (r *Repo) LoadBulk(
+	ctx context.Context,
+	indexName string,
+	prefix string,
+	records []any,
+	keyFn func(any) string,
+	opts ...WriteOpt,
+) (*LoadReport, error) {
+	cfg := newWriteCfg(opts)
+	rep := &LoadReport{}
+	recs := make([]loadRecord, 0, len(records))
+	for i, rec := range records {
+		key := keyFn(rec)
+		if !strings.HasPrefix(key, prefix) {
+			key = prefix + key
+		}
+		vals, err := structToMap(rec)
+		if err != nil {
+			rep.Errors = append(rep.Errors, RecordError{Index: i, Key: key, Err: err})
+			continue
+		}
+		recs = append(recs, loadRecord{
+			index: i, key: key, cmd: hset(key, vals), size: payloadSize(key, vals), rec: rec,
+		})
+	}
+	return loadRecords(ctx, r.exec, rep, recs, len(records), cfg)
+}
+
+// loadRecords writes recs in concurrent chunks, adding the outcome to rep.
+// total is the number of records the caller was given.
+func loadRecords(
+	ctx context.Context,
+	exec driver.Executor,
+	rep *LoadReport,
+	recs []loadRecord,
+	total int,
+	cfg *writeCfg,
+) (*LoadReport, error) {
+	if cfg.chunk <= 0 {
+		cfg.chunk = defaultBulkBatch
+	}
+	if cfg.workers <= 0 {
+		cfg.workers = defaultLoadWorkers
+	}
+
+	var (
+		mu  sync.Mutex
+		wg  sync.WaitGroup
+		sem = make(chan struct{}, cfg.workers)
+	)
+	for _, chunk := range internal.Chunk(recs, cfg.chunk) {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(chunk []loadRecord) {
+			defer func() { <-sem; wg.Done() }()
+			written, skipped, errs := loadChunk(ctx, exec, chunk, cfg)
+			mu.Lock()
+			rep.Written += written
+			rep.Skipped += skipped
+			rep.Errors = append(rep.Errors, errs...)
+			mu.Unlock()
+		}(chunk)
+	}
+	wg.Wait()
+	return rep, rep.err(total)
+}
+
+// err sorts the report's errors and summarises them, nil if there are none.
+func (rep *LoadReport) err(total int) error {
+	if len(rep.Errors) == 0 {
+		return nil
+	}
+	sort.Slice(rep.Errors, func(i, j int) bool { return rep.Errors[i].Index < rep.Errors[j].Index })
+	return fmt.Errorf("repository: %d of %d records failed: %w",
+		len(rep.Errors), total, rep.Errors[0])
+}
+
+// loadChunk writes chunk as one pipeline, applying cfg the way writeDoc
+// does for a single record.
+func loadChunk(
+	ctx context.Context,
+	exec driver.Executor,
+	chunk []loadRecord,
+	cfg *writeCfg,
+) (written, skipped int, errs []RecordError) {
+	fail := func(recs []loadRecord, err error) {
+		for _, rc := range recs {
+			errs = append(errs, RecordError{Index: rc.index, Key: rc.key, Err: err})
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		fail(chunk, err)
+		return 0, 0, errs
+	}
+	if err := cfg.checkExec(exec, true); err != nil {
+		fail(chunk, err)
+		return 0, 0, errs
+	}
+
+	ids := make([]string, len(chunk))
+	for i, rc := range chunk {
+		ids[i] = rc.key
+		if cfg.seenID != nil {
+			ids[i] = cfg.seenID(rc.key, rc.rec)
+		}
+	}
+	if cfg.seenFilter != "" {
+		seen, err := seenBatch(ctx, exec, cfg, ids)
+		if err != nil {
+			fail(chunk, err)
+			return 0, 0, errs
+		}
+		live, liveIDs := chunk[:0:0], ids[:0:0]
+		for i, rc := range chunk {
+			if seen[i] {
+				skipped++
+				continue
+			}
+			live, liveIDs = append(live, rc), append(liveIDs, ids[i])
+		}
+		chunk, ids = live, liveIDs
+	}
+	if len(chunk) == 0 {
+		return 0, skipped, nil
+	}
+
+	if cfg.limiter != nil {
+		size := 0
+		for _, rc := range chunk {
+			size += rc.size
+		}
+		if err := cfg.limiter.Wait(ctx, len(chunk), size); err != nil {
+			fail(chunk, err)
+			return 0, skipped, errs
+		}
+	}
+
+	// owner[i] is the chunk position of the record cmds[i] belongs to
+	var cmds [][]interface{}
+	var owner []int
+	for i, rc := range chunk {
+		cmds, owner = append(cmds, rc.cmd), append(owner, i)
+		if cfg.ttl > 0 {
+			cmds = append(cmds, []interface{}{"PEXPIRE", rc.key, max(cfg.ttl.Milliseconds(), 1)})
+			owner = append(owner, i)
+		}
+	}
+	res, err := cfg.send(ctx, exec, cmds, true)
+	if err != nil {
+		fail(chunk, err)
+		return 0, skipped, errs
+	}
+
+	failed := make([]error, len(chunk))
+	for i, reply := range res[:len(owner)] {
+		if err, ok := reply.(error); ok && failed[owner[i]] == nil {
+			failed[owner[i]] = err
+		}
+	}
+	var ok []loadRecord
+	var okIDs []string
+	for i, rc := range chunk {
+		if failed[i] != nil {
+			errs = append(errs, RecordError{Index: rc.index, Key: rc.key, Err: failed[i]})
+			continue
+		}
+		ok, okIDs = append(ok, rc), append(okIDs, ids[i])
+	}
+	written = len(ok)
+	if len(ok) == 0 {
+		return 0, skipped, errs
+	}
+
+	// post-write checks: the records are stored, but the caller asked for more
+	var post error
+	if cfg.replicas > 0 {
+		switch n, isInt := res[len(res)-1].(int64); {
+		case !isInt:
+			post = fmt.Errorf("repository: unexpected WAIT reply %T", res[len(res)-1])
+		case int(n) < cfg.replicas:
+			post = &ReplicationError{Key: ok[len(ok)-1].key, Want: cfg.replicas, Got: int(n)}
+		}
+	}
+	if post == nil && cfg.seenFilter != "" {
+		post = markSeen(ctx, exec, cfg, okIDs)
+	}
+	if post == nil && cfg.visibleIn != "" {
+		post = awaitIndexed(ctx, exec, ok[len(ok)-1].key, cfg, true)
+	}
+	if post != nil {
+		fail(ok, post)
+		written = 0
+	}
+	return written, skipped, errs
+}
+
+// seenBatch is inFilter for many ids in one pipeline.
+func seenBatch(ctx context.Context, exec driver.Executor, cfg *writeCfg, ids []string) ([]bool, error) {
+	cmd := "BF.EXISTS"
+	if cfg.seenCuckoo {
+		cmd = "CF.EXISTS"
+	}
+	cmds := make([][]interface{}, len(ids))
+	for i, id := range ids {
+		cmds[i] = []interface{}{cmd, cfg.seenFilter, id}
+	}
+	res, err := driver.DoBatch(ctx, exec, cmds)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]bool, len(ids))
+	for i, reply := range res {
+		switch v := reply.(type) {
+		case error:
+			return nil, v
+		case int64: // RESP-2
+			out[i] = v == 1
+		case bool: // RESP-3
+			out[i] = v
+		default:
+			return nil, fmt.Errorf("repository: unexpected %s reply %T", cmd, reply)
+		}
+	}
+	return out, nil
+}
+
+// markSeen is updateFilter for many written ids in one pipeline.
+func markSeen(ctx context.Context, exec driver.Executor, cfg *writeCfg, ids []string) error {
+	cmd := "BF.ADD"
+	if cfg.seenCuckoo {
+		cmd = "CF.ADD"
+	}
+	cmds := make([][]interface{}, len(ids))
+	for i, id := range ids {
+		cmds[i] = []interface{}{cmd, cfg.seenFilter, id}
+	}
+	res, err := driver.DoBatch(ctx, exec, cmds)
+	if err != nil {
+		return err
+	}
+	for _, reply := range res {
+		if err, ok := reply.(error); ok {
+			return err
+		}
+	}
+	return nil
+}
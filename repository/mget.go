@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/manojoshi/redisorm/driver"
+	"github.com/manojoshi/redisorm/scan"
+)
+
+// SkipMissing makes MGet leave out IDs with no stored document instead of
+// failing with ErrNotFound.
+func SkipMissing() Opt {
+	return optFunc{
+		load: func(c *loadCfg) { c.skipMissing = true },
+	}
+}
+
+// MGet loads the entities with the given primary keys in one pipelined
+// round trip of HGETALL (JSON.GET for AsJSON repositories) – the "hydrate
+// N entities by ID" path, no FT.SEARCH involved:
+//
+//	orders, err := repository.MGet[Order](ctx, repo, ids)
+//
+// Results are in ids order.  A missing ID fails the call with ErrNotFound
+// naming its key, unless SkipMissing is given.  Preload applies as for Find.
+func MGet[T any](ctx context.Context, r *Repository, ids []string, opts ...Opt) ([]T, error) {
+	cfg := &loadCfg{}
+	for _, o := range opts {
+		o.applyLoad(cfg)
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	model := reflect.TypeOf((*T)(nil)).Elem()
+	cmds := make([][]interface{}, len(ids))
+	for i, id := range ids {
+		key, err := r.idKey(id, model)
+		if err != nil {
+			return nil, err
+		}
+		cmds[i] = []interface{}{"HGETALL", key}
+		if r.json {
+			cmds[i] = []interface{}{"JSON.GET", key, "$"}
+		}
+	}
+	replies, err := driver.DoBatch(ctx, r.exec, cmds)
+	if err != nil {
+		return nil, err
+	}
+
+	hits := make([]scan.Hit, 0, len(ids))
+	for i, reply := range replies {
+		key := cmds[i][1].(string)
+		var fields map[string]string
+		switch v := reply.(type) {
+		case error:
+			if !errors.Is(v, redis.Nil) {
+				return nil, v
+			}
+		case nil:
+		default:
+			if r.json {
+				fields, err = jsonFields(fmt.Sprint(v), model)
+			} else {
+				fields, err = scan.HashFields(v)
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+		if len(fields) == 0 {
+			if cfg.skipMissing {
+				continue
+			}
+			return nil, fmt.Errorf("repository: %s: %w", key, ErrNotFound)
+		}
+		hits = append(hits, scan.Hit{Key: key, Fields: fields})
+	}
+
+	out, err := scan.Docs[T](hits)
+	if err != nil {
+		return nil, err
+	}
+	if err := loaded(ctx, r, out, cfg); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/manojoshi/redisorm/driver"
+	"github.com/manojoshi/redisorm/index"
+	q "github.com/manojoshi/redisorm/query"
+)
+
+// ForModel binds a repository to the naming conventions of model type T, so
+// index creation, the repository and seeding agree without repeating
+// "order_idx" / "order:" by hand:
+//
+//	orders := repository.ForModel[Order](conn)      // order_idx, keys order:<pk>
+//	if err := orders.EnsureIndex(ctx); err != nil { // FT.CREATE order_idx PREFIX 1 order:
+//	    log.Fatal(err)
+//	}
+//	err := orders.Save(ctx, &Order{ID: "42"})       // HSET order:42 …
+//
+// The index name is index.NameFor and the key prefix index.PrefixFor;
+// opts are applied afterwards and may override either.
+func ForModel[T any](exec driver.Executor, opts ...Option) *Repository {
+	var model T
+	opts = append([]Option{WithPrefix(index.PrefixFor(model))}, opts...)
+	r := New(index.NameFor(model), exec, opts...)
+	r.model = model
+	return r
+}
+
+// EnsureIndex creates the index of a ForModel repository from its model,
+// under the repository's index name and key prefix (ON JSON for AsJSON
+// repositories).  opts are passed on to index.AutoCreate.
+func (r *Repository) EnsureIndex(ctx context.Context, opts ...index.CreateOpt) error {
+	if r.model == nil {
+		return ErrNoModel
+	}
+	base := []index.CreateOpt{index.WithName(r.index)}
+	if r.prefix != "" {
+		base = append(base, index.WithPrefixes(r.prefix))
+	}
+	if r.json {
+		base = append(base, index.OnJSON())
+	}
+	return index.AutoCreate(ctx, r.exec, r.model, append(base, opts...)...)
+}
+
+// StrictFields makes every search and aggregation of a ForModel repository
+// check the fields it names against the model before sending it; unknown
+// ones fail the call with a *q.UnknownFieldError.  See SearchBuilder.Schema.
+func StrictFields() Option {
+	return func(r *Repository) { r.strict = true }
+}
+
+// newSearch starts a search of where on the repository's index, encoding
+// range bounds for the ForModel type (see SearchBuilder.Model) and bound to
+// it under StrictFields.  It is the one place the query middleware
+// runs, so no search path can skip it.
+func (r *Repository) newSearch(where q.Expr) *q.SearchBuilder {
+	sb := q.NewSearch(r.index).Where(r.rewritten(where))
+	if r.model != nil {
+		sb.Model(r.model)
+	}
+	if r.strict && r.model != nil {
+		sb.Schema(r.model)
+	}
+	return sb
+}
+
+// newAggregate is newSearch for FT.AGGREGATE.
+func (r *Repository) newAggregate(where q.Expr) *q.AggregateBuilder {
+	ab := q.NewAggregate(r.index).Where(r.rewritten(where))
+	if r.model != nil {
+		ab.Model(r.model)
+	}
+	if r.strict && r.model != nil {
+		ab.Schema(r.model)
+	}
+	return ab
+}
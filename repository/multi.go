@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/manojoshi/redisorm/driver"
+	"github.com/manojoshi/redisorm/merge"
+	q "github.com/manojoshi/redisorm/query"
+	"github.com/manojoshi/redisorm/scan"
+)
+
+// MultiIndexSearcher runs one query against several indexes concurrently
+// (orders_2023_idx, orders_2024_idx, …) and merges the answers as if they
+// came from a single index.
+//
+//	ms := repository.NewMultiIndexSearcher(conn, "orders_2023_idx", "orders_2024_idx").
+//	    MergeBySort("created_ts", q.Desc).
+//	    DedupBy("order_id").
+//	    Limit(0, 50)
+//	res, err := ms.Search(ctx, q.Eq("status", "PENDING"))
+type MultiIndexSearcher struct {
+	exec          driver.Executor
+	indexes       []string
+	sortField     string
+	dir           q.Dir
+	byScore       bool
+	dedupField    string
+	offset, limit int
+}
+
+// MultiResult is the merged answer of a MultiIndexSearcher.  Errors holds the
+// indexes that failed; their documents are simply missing from Hits.
+type MultiResult struct {
+	Hits   []scan.Hit
+	Errors map[string]error
+}
+
+// NewMultiIndexSearcher binds the searcher to the indexes to fan out to.
+// Without a merge policy, hits are concatenated in index order.
+func NewMultiIndexSearcher(exec driver.Executor, indexes ...string) *MultiIndexSearcher {
+	return &MultiIndexSearcher{exec: exec, indexes: indexes, limit: 10_000}
+}
+
+// MergeBySort sorts every index on field and merges on the same key.  The
+// field must be part of the returned document (keep it in any Select list).
+func (m *MultiIndexSearcher) MergeBySort(field string, dir q.Dir) *MultiIndexSearcher {
+	m.sortField, m.dir, m.byScore = field, dir, false
+	return m
+}
+
+// MergeByScore requests WITHSCORES and merges by descending relevance.
+// Scores are only comparable across indexes that share scorer and corpus
+// statistics, so use this for shards of one logical dataset.
+func (m *MultiIndexSearcher) MergeByScore() *MultiIndexSearcher {
+	m.byScore, m.sortField = true, ""
+	return m
+}
+
+// DedupBy drops later hits carrying an already-seen value of field (usually
+// the PK).  An empty field dedups on the Redis key, as do hits that lack
+// the field.
+func (m *MultiIndexSearcher) DedupBy(field string) *MultiIndexSearcher {
+	m.dedupField = field
+	return m
+}
+
+// Limit applies a global window to the merged result.
+func (m *MultiIndexSearcher) Limit(offset, limit int) *MultiIndexSearcher {
+	m.offset, m.limit = offset, limit
+	return m
+}
+
+// Search fans the query out and merges.  The error is non-nil only when
+// every index failed; partial failures are reported in MultiResult.Errors.
+func (m *MultiIndexSearcher) Search(ctx context.Context, where q.Expr, opts ...Opt) (*MultiResult, error) {
+	lists := make([][]scan.Hit, len(m.indexes))
+	errs := make([]error, len(m.indexes))
+
+	var wg sync.WaitGroup
+	for i, idx := range m.indexes {
+		wg.Add(1)
+		go func(i int, idx string) {
+			defer wg.Done()
+			lists[i], errs[i] = m.searchOne(ctx, idx, where, opts)
+		}(i, idx)
+	}
+	wg.Wait()
+
+	res := &MultiResult{}
+	for i, err := range errs {
+		if err == nil {
+			continue
+		}
+		if res.Errors == nil {
+			res.Errors = make(map[string]error)
+		}
+		res.Errors[m.indexes[i]] = fmt.Errorf("repository: %s: %w", m.indexes[i], err)
+	}
+	if len(m.indexes) > 0 && len(res.Errors) == len(m.indexes) {
+		return nil, errors.Join(errs...)
+	}
+
+	hits := merge.KWay(lists, m.less())
+	hits = merge.Dedup(hits, func(h scan.Hit) string {
+		if v, ok := h.Fields[m.dedupField]; ok && m.dedupField != "" {
+			return v
+		}
+		return h.Key // without the field, hits are only duplicates of their own key
+	})
+	res.Hits = merge.Window(hits, m.offset, m.limit)
+	return res, nil
+}
+
+// searchOne asks a single index for the first offset+limit hits, which is
+// all the global window can ever need from it.
+func (m *MultiIndexSearcher) searchOne(ctx context.Context, idx string, where q.Expr, opts []Opt) ([]scan.Hit, error) {
+	sb := q.NewSearch(idx).Using(m.exec)
+	if where != nil {
+		sb.Where(where)
+	}
+	for _, o := range opts {
+		o.applySearch(sb)
+	}
+	if m.sortField != "" {
+		sb.SortBy(m.sortField, m.dir)
+	}
+	if m.byScore {
+		sb.WithScores()
+	}
+	sb.Limit(0, m.offset+m.limit)
+
+	args, err := sb.RawArgs()
+	if err != nil {
+		return nil, err
+	}
+	raw, err := m.exec.Do(ctx, args...)
+	if err != nil {
+		return nil, driver.Wrap(args, err)
+	}
+	return sb.DecodeHits(raw)
+}
+
+// less picks the merge order matching the configured policy.
+func (m *MultiIndexSearcher) less() func(a, b scan.Hit) bool {
+	switch {
+	case m.byScore:
+		return func(a, b scan.Hit) bool { return a.Score > b.Score }
+	case m.sortField != "":
+		return merge.ByKey(func(h scan.Hit) string { return h.Fields[m.sortField] }, m.dir == q.Desc)
+	}
+	return func(a, b scan.Hit) bool { return false } // keep index order
+}
@@ -1,18 +1,25 @@
 package repository
 
-import q "github.com/manojoshi/redisorm/query"
+import (
+	"strconv"
+	"strings"
+
+	q "github.com/manojoshi/redisorm/query"
+)
 
 // Opt is applied to whichever builder is in play.  If the helper doesn’t make
 // sense for that builder the method is left nil and becomes a no-op.
 type Opt interface {
 	applySearch(*q.SearchBuilder)
 	applyAgg(*q.AggregateBuilder)
+	applyLoad(*loadCfg)
 }
 
 // optFunc is a concrete Opt implementation that holds functions for
 type optFunc struct {
 	search func(*q.SearchBuilder)
 	agg    func(*q.AggregateBuilder)
+	load   func(*loadCfg)
 }
 
 // applySearch applies the Opt to the SearchBuilder if it is not nil.
@@ -29,6 +36,13 @@ func (o optFunc) applyAgg(b *q.AggregateBuilder) {
 	}
 }
 
+// applyLoad applies the Opt to the decode phase of Find if it is not nil.
+func (o optFunc) applyLoad(c *loadCfg) {
+	if o.load != nil {
+		o.load(c)
+	}
+}
+
 // ---------- COMMON helpers ----------
 
 // Select applies a list of fields to be returned by FT.SEARCH or FT.AGGREGATE.
@@ -46,6 +60,103 @@ func Limit(offset, limit int) Opt {
 	}
 }
 
+// MinScore drops search hits scoring below min (implies WITHSCORES).  The
+// cut-off happens client-side after LIMIT, so pages may be short.
+func MinScore(min float64) Opt {
+	return optFunc{
+		search: func(b *q.SearchBuilder) { b.MinScore(min) },
+	}
+}
+
+// WithScores returns relevance scores; Find fills them into a float64 field
+// tagged `redisorm:"__score"` (and keys into `redisorm:"__key"`).
+func WithScores() Opt {
+	return optFunc{
+		search: func(b *q.SearchBuilder) { b.WithScores() },
+	}
+}
+
+// Highlight marks the query terms in the given TEXT fields (all of them
+// when fields is empty) with openTag/closeTag, "<b>"/"</b>" by default.  The
+// returned documents carry the marked-up text in place of the field value,
+// so decoded structs and Doc getters see it directly.
+//
+//	repo.Search(ctx, q.Text("wireless"), repository.Highlight([]string{"title"}, "<em>", "</em>"))
+func Highlight(fields []string, openTag, closeTag string) Opt {
+	return optFunc{
+		search: func(b *q.SearchBuilder) { b.Highlight(fields, openTag, closeTag) },
+	}
+}
+
+// Summarize shortens the given TEXT fields (all of them when fields is
+// empty) to frags fragments of length words around the matched terms,
+// joined by sep; zero values keep the server defaults.  Like Highlight it
+// replaces the field value in the results, and the two combine into
+// highlighted snippets.
+func Summarize(fields []string, frags, length int, sep string) Opt {
+	return optFunc{
+		search: func(b *q.SearchBuilder) { b.Summarize(fields, frags, length, sep) },
+	}
+}
+
+// Scorer picks the full-text scoring function (q.ScorerBM25,
+// q.ScorerTFIDF, …); pair it with WithScores to read the scores back.
+func Scorer(name string) Opt {
+	return optFunc{
+		search: func(b *q.SearchBuilder) { b.Scorer(name) },
+	}
+}
+
+// InKeys restricts the search to the given document keys, e.g. to re-rank
+// a candidate set produced elsewhere.
+func InKeys(keys ...string) Opt {
+	return optFunc{
+		search: func(b *q.SearchBuilder) { b.InKeys(keys...) },
+	}
+}
+
+// InFields limits full-text matching to the given TEXT fields.
+func InFields(fields ...string) Opt {
+	return optFunc{
+		search: func(b *q.SearchBuilder) { b.InFields(fields...) },
+	}
+}
+
+// Dedup keeps the first search hit per distinct value of field; combine it
+// with SortDesc to get "latest X per Y".  Runs client-side on the page.
+func Dedup(field string) Opt {
+	return optFunc{
+		search: func(b *q.SearchBuilder) { b.Dedup(field) },
+	}
+}
+
+// Params binds q.Param placeholders used in the where clause.
+func Params(kv map[string]any) Opt {
+	return optFunc{
+		search: func(b *q.SearchBuilder) { b.Params(kv) },
+		agg:    func(b *q.AggregateBuilder) { b.Params(kv) },
+	}
+}
+
+// Near keeps documents whose GEO field lies within radius of lon/lat.  It
+// narrows the where clause, so it works for Search and Aggregate alike.
+func Near(field string, lon, lat, radius float64, unit q.GeoUnit) Opt {
+	return andWhere(q.GeoRadius(field, lon, lat, radius, unit))
+}
+
+// WithinBox keeps documents whose GEOSHAPE field lies within the lon/lat
+// rectangle, see q.GeoBox.
+func WithinBox(field string, minLon, minLat, maxLon, maxLat float64) Opt {
+	return andWhere(q.GeoBox(field, minLon, minLat, maxLon, maxLat))
+}
+
+func andWhere(e q.Expr) Opt {
+	return optFunc{
+		search: func(b *q.SearchBuilder) { b.AndWhere(e) },
+		agg:    func(b *q.AggregateBuilder) { b.AndWhere(e) },
+	}
+}
+
 // SortAsc SORT
 func SortAsc(field string) Opt  { return sortOpt(field, q.Asc) }
 func SortDesc(field string) Opt { return sortOpt(field, q.Desc) }
@@ -64,6 +175,16 @@ func Group(keys ...q.GroupKey) Opt {
 	}
 }
 
+// Apply computes expr into alias.  Options run in order, so an Apply given
+// before Group can be a Group key or reducer input: Apply("day(@created_ts)",
+// "day"), Group(q.By("day")); one given after Group runs on the grouped rows:
+// Group(q.By("sku")), Sum("qty", "total"), Count("n"), Apply("@total/@n", "avg").
+func Apply(expr, alias string) Opt {
+	return optFunc{
+		agg: func(b *q.AggregateBuilder) { b.ApplyExpr(expr, alias) },
+	}
+}
+
 func Count(alias string) Opt {
 	return optFunc{
 		agg: func(b *q.AggregateBuilder) { b.Reduce("COUNT", "", alias) },
@@ -81,3 +202,68 @@ func Avg(field, alias string) Opt {
 		agg: func(b *q.AggregateBuilder) { b.Reduce("AVG", field, alias) },
 	}
 }
+
+// Min, Max and StdDev reduce a NUMERIC field per group.
+func Min(field, alias string) Opt    { return reduce("MIN", field, alias) }
+func Max(field, alias string) Opt    { return reduce("MAX", field, alias) }
+func StdDev(field, alias string) Opt { return reduce("STDDEV", field, alias) }
+
+// CountDistinct counts the distinct values of field per group.
+func CountDistinct(field, alias string) Opt { return reduce("COUNT_DISTINCT", field, alias) }
+
+// ToList collects the distinct values of field per group into a list.
+func ToList(field, alias string) Opt { return reduce("TOLIST", field, alias) }
+
+// Quantile is the q-quantile (0–1) of field per group: Quantile("qty", 0.99, "p99").
+func Quantile(field string, q float64, alias string) Opt {
+	return reduceArgs("QUANTILE", alias, "@"+strings.TrimPrefix(field, "@"), strconv.FormatFloat(q, 'f', -1, 64))
+}
+
+// FirstValue is field of the first row in each group, in no particular order.
+func FirstValue(field, alias string) Opt { return reduce("FIRST_VALUE", field, alias) }
+
+// FirstValueBy is field of the first row in each group when ordered by the
+// by field: FirstValueBy("status", "created_ts", q.Desc, "last_status").
+func FirstValueBy(field, by string, dir q.Dir, alias string) Opt {
+	return reduceArgs("FIRST_VALUE", alias,
+		"@"+strings.TrimPrefix(field, "@"), "BY", "@"+strings.TrimPrefix(by, "@"), string(dir))
+}
+
+func reduce(fn, field, alias string) Opt {
+	return optFunc{
+		agg: func(b *q.AggregateBuilder) { b.Reduce(fn, field, alias) },
+	}
+}
+
+func reduceArgs(fn, alias string, args ...string) Opt {
+	return optFunc{
+		agg: func(b *q.AggregateBuilder) { b.ReduceArgs(fn, alias, args...) },
+	}
+}
+
+// AggSortAsc and AggSortDesc order aggregate rows by a group key or reducer
+// alias; several options sort by each in turn.
+func AggSortAsc(field string) Opt  { return aggSort(q.SortAsc(field)) }
+func AggSortDesc(field string) Opt { return aggSort(q.SortDesc(field)) }
+
+func aggSort(k q.SortKey) Opt {
+	return optFunc{
+		agg: func(b *q.AggregateBuilder) { b.SortBy(k) },
+	}
+}
+
+// Having keeps only aggregate rows matching "@field op v" after reducers ran:
+// Having("orders", ">", 10).
+func Having(field, op string, v any) Opt {
+	return optFunc{
+		agg: func(b *q.AggregateBuilder) { b.Having(field, op, v) },
+	}
+}
+
+// Filter is Having with a verbatim FILTER expression, for conditions over
+// several columns: Filter("@avg_qty > 3 && @orders > 10").
+func Filter(expr string) Opt {
+	return optFunc{
+		agg: func(b *q.AggregateBuilder) { b.Filter(expr) },
+	}
+}
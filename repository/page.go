@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+
+	q "github.com/manojoshi/redisorm/query"
+	"github.com/manojoshi/redisorm/scan"
+)
+
+// DefaultPageSize is the SearchPage page size when PageRequest.Size is 0.
+const DefaultPageSize = 50
+
+// PageRequest asks SearchPage for one page.
+type PageRequest struct {
+	Size  int    // documents per page (DefaultPageSize if 0)
+	Token string // next token of the previous page, "" for the first
+}
+
+// WithPageSecret signs SearchPage tokens with HMAC-SHA256 under secret, so
+// clients can neither read nor forge them.  Without it tokens are still
+// opaque and checksummed, but anyone knowing the format can mint one.
+func WithPageSecret(secret []byte) Option {
+	return func(r *Repository) { r.tokens = q.NewTokens(secret) }
+}
+
+// SearchPage is Search for paginated APIs, using keyset (search-after)
+// paging instead of OFFSET, whose cost grows with the page depth:
+//
+//	docs, next, err := repo.SearchPage(ctx, q.Eq("status", "PENDING"),
+//	    repository.PageRequest{Size: 100, Token: token},
+//	    repository.SortDesc("created_ts"))
+//
+// Sort on a SORTABLE NUMERIC field: the token records its last value and
+// the key of the last document, and the next page is a range query from
+// that value.  Documents sharing the boundary value are skipped up to the
+// recorded key, so only the tie run is ever re-read.  The token's sort
+// order overrides opts, so a page sequence cannot switch order midway.
+// Without a numeric sort field, pages fall back to OFFSET.  next is ""
+// after the last page; Limit options are overridden by req.Size.
+func (r *Repository) SearchPage(
+	ctx context.Context,
+	where q.Expr,
+	req PageRequest,
+	opts ...Opt,
+) (docs []*scan.Doc, next string, err error) {
+	tokens := r.tokens
+	if tokens == nil {
+		tokens = q.NewTokens(nil)
+	}
+	cur, err := tokens.Decode(req.Token)
+	if err != nil {
+		return nil, "", err
+	}
+	size := req.Size
+	if size <= 0 {
+		size = DefaultPageSize
+	}
+
+	sb := r.newSearch(r.scoped(where, nil, opts)).
+		Using(r.exec)
+	for _, opt := range opts {
+		opt.applySearch(sb)
+	}
+	if cur.Field != "" {
+		sb.SortBy(cur.Field, cur.Dir)
+	}
+	field, dir := sb.Sorting()
+	if cur.Last != "" {
+		// from the boundary value on, re-reading at most its tie run
+		bound := q.Gte(field, cur.Last)
+		if dir == q.Desc {
+			bound = q.Lte(field, cur.Last)
+		}
+		sb.AndWhere(bound).Limit(0, cur.Ties+size)
+	} else {
+		sb.Limit(cur.Offset, size)
+	}
+
+	res, err := sb.RunResult(ctx)
+	if err != nil && !errors.Is(err, q.ErrPartialResults) {
+		return nil, "", err
+	}
+	hits, skipped := res.Hits, 0
+	if cur.Last != "" {
+		skipped = min(cur.Ties, len(hits)) // key gone: trust the count
+		for i := 0; i < len(hits) && i <= cur.Ties; i++ {
+			if hits[i].Key == cur.Key {
+				skipped = i + 1
+				break
+			}
+		}
+		hits = hits[skipped:min(skipped+size, len(hits))]
+	}
+
+	rows := make([]map[string]string, len(hits))
+	for i, h := range hits {
+		rows[i] = h.Fields
+	}
+	if jerr := r.expandRows(rows); jerr != nil {
+		return nil, "", jerr
+	}
+	if len(hits) == size {
+		next = tokens.Encode(nextCursor(cur, field, dir, hits, rows, skipped))
+	}
+	return scan.ToDocs(rows), next, err
+}
+
+// nextCursor is the cursor of the page after hits (with decoded rows),
+// fetched from cur after skipping its first skipped hits.
+func nextCursor(cur q.Cursor, field string, dir q.Dir, hits []scan.Hit, rows []map[string]string, skipped int) q.Cursor {
+	f := strings.TrimPrefix(field, "@")
+	last, ok := rows[len(rows)-1][f]
+	if _, err := strconv.ParseFloat(last, 64); field == "" || !ok || err != nil {
+		if cur.Last != "" { // keyset page whose rows lack the field
+			return q.Cursor{Field: field, Dir: dir, Last: cur.Last, Ties: skipped + len(hits)}
+		}
+		return q.Cursor{Offset: cur.Offset + len(hits)}
+	}
+	c := q.Cursor{Field: field, Dir: dir, Last: last, Key: hits[len(hits)-1].Key}
+	for i := len(rows) - 1; i >= 0 && rows[i][f] == last; i-- {
+		c.Ties++
+	}
+	if c.Ties == len(rows) && cur.Last == last { // the whole page was one tie run
+		c.Ties += skipped
+	}
+	return c
+}
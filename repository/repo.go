@@ -13,20 +13,125 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"iter"
 
 	"github.com/manojoshi/redisorm/driver"
 	q "github.com/manojoshi/redisorm/query"
+	"github.com/manojoshi/redisorm/scan"
 )
 
 // Repository is generic over the domain model.
 type Repository struct {
-	index string
-	exec  driver.Executor
+	index   string
+	exec    driver.Executor
+	keyFn   KeyFunc
+	prefix  string                // WithPrefix: key = prefix + primary key
+	json    bool                  // AsJSON: RedisJSON documents instead of hashes
+	model   any                   // ForModel: zero value of the bound model type
+	idGen   Generator             // WithIDGenerator: fills empty PKs on Save
+	hooks   []Hooks               // WithHooks, in registration order
+	tokens  *q.Tokens             // WithPageSecret: SearchPage token signer
+	rewrite []func(q.Expr) q.Expr // WithQueryMiddleware, in registration order
+	strict  bool                  // StrictFields: check field names against model
+	err     error                 // deferred configuration error (bad key template, …)
 }
 
+// Option configures a Repository at construction time.  (Opt, by contrast,
+// tunes a single Search / Aggregate call.)
+type Option func(*Repository)
+
 // New constructs a repository bound to a RediSearch index.
-func New(index string, exec driver.Executor) *Repository {
-	return &Repository{index: index, exec: exec}
+func New(index string, exec driver.Executor, opts ...Option) *Repository {
+	r := &Repository{index: index, exec: exec}
+	for _, o := range opts {
+		o(r)
+	}
+	return r
+}
+
+// WithKeyFunc sets how entity keys are derived.
+func WithKeyFunc(fn KeyFunc) Option {
+	return func(r *Repository) { r.keyFn, r.prefix = fn, "" }
+}
+
+// WithKeyTemplate sets the key naming scheme from a template, see KeyTemplate.
+// A malformed template is reported by the first call that needs a key.
+func WithKeyTemplate(tmpl string) Option {
+	return func(r *Repository) {
+		kf, err := KeyTemplate(tmpl)
+		if err != nil {
+			r.err = err
+			return
+		}
+		r.keyFn, r.prefix = kf, ""
+	}
+}
+
+// WithPrefix sets the classic key scheme: prefix + the value of the field
+// tagged PK ("order:" + order_id).  It also lets Get / Delete / Exists
+// address entities by bare ID.
+func WithPrefix(prefix string) Option {
+	return func(r *Repository) { r.prefix, r.keyFn = prefix, nil }
+}
+
+// WithQueryMiddleware makes every query of the repository – searches,
+// aggregations, counts and bulk updates / deletes – pass through fn before
+// it is sent, e.g. to inject a tenant filter or strip conditions callers may
+// not use (see q.Rewrite).  fn receives MatchAll for unfiltered queries.  It
+// may be given several times; middleware runs in registration order.
+// A q.Raw node (and so every SearchRaw query) is opaque to fn: it can be
+// ANDed with further conditions, but not inspected or rewritten.
+//
+//	repo := repository.New("order_idx", conn,
+//	    repository.WithQueryMiddleware(func(where q.Expr) q.Expr {
+//	        return q.AndSkipNil(where, q.Eq("tenant_id", tenant))
+//	    }))
+func WithQueryMiddleware(fn func(q.Expr) q.Expr) Option {
+	return func(r *Repository) { r.rewrite = append(r.rewrite, fn) }
+}
+
+// rewritten passes where through the query middleware.
+func (r *Repository) rewritten(where q.Expr) q.Expr {
+	if len(r.rewrite) == 0 {
+		return where
+	}
+	if where == nil {
+		where = q.MatchAll()
+	}
+	for _, fn := range r.rewrite {
+		where = fn(where)
+	}
+	return where
+}
+
+// Debug logs every command this repository sends, with round-trip and
+// decode timings, through the driver's logging hook (see driver.SetLogger).
+// driver.SetDebug does the same process-wide.
+func Debug() Option {
+	return func(r *Repository) {
+		if r.exec != nil {
+			r.exec = driver.Debug(r.exec)
+		}
+	}
+}
+
+// Key returns the Redis key record is stored under.
+func (r *Repository) Key(record any) (string, error) {
+	if r.err != nil {
+		return "", r.err
+	}
+	if r.keyFn == nil && r.prefix != "" {
+		pk, err := pkValue(record)
+		if err != nil {
+			return "", err
+		}
+		return r.prefix + pk, nil
+	}
+	if r.keyFn == nil {
+		return "", errors.New("repository: no key scheme configured (use WithPrefix or WithKeyTemplate)")
+	}
+	return r.keyFn(record)
 }
 
 // -------------------------------------------------------------------
@@ -34,21 +139,183 @@ func New(index string, exec driver.Executor) *Repository {
 // -------------------------------------------------------------------
 
 // Search executes a FT.SEARCH using the provided where Expr and any search
-// options (Select, SortAsc, Limit, …). It decodes the results directly into map[string]string
+// options (Select, SortAsc, Limit, …). Results come back as scan.Docs, maps
+// with typed getters; use Find for struct decoding.
 func (r *Repository) Search(
 	ctx context.Context,
 	where q.Expr,
 	opts ...Opt,
-) ([]map[string]string, error) {
+) ([]*scan.Doc, error) {
+
+	where = r.scoped(where, nil, opts)
+	sb := r.newSearch(where).
+		Using(r.exec)
+
+	cfg := &loadCfg{}
+	for _, opt := range opts {
+		opt.applySearch(sb)
+		opt.applyLoad(cfg)
+	}
+	rows, err := sb.Run(ctx)
+	if err == nil && len(rows) == 0 {
+		var fixed q.Expr
+		if fixed, err = r.corrected(ctx, where, cfg); fixed != nil {
+			rows, err = sb.Where(r.rewritten(fixed)).Run(ctx)
+		}
+	}
+	if err != nil && !errors.Is(err, q.ErrPartialResults) {
+		return nil, err
+	}
+	if jerr := r.expandRows(rows); jerr != nil {
+		return nil, jerr
+	}
+	return scan.ToDocs(rows), err
+}
+
+// SearchRaw is Search for a hand-written RediSearch query string, for
+// syntax the query AST does not cover yet; options, soft-delete scoping and
+// decoding apply as usual.  Query middleware sees the string as one opaque
+// q.Raw node, so it can only add conditions around it.  See q.Raw.
+//
+//	docs, err := repo.SearchRaw(ctx, "@title:(red|blue) ~@tags:{sale}",
+//	    repository.Limit(0, 20))
+func (r *Repository) SearchRaw(ctx context.Context, query string, opts ...Opt) ([]*scan.Doc, error) {
+	return r.Search(ctx, q.Raw(query), opts...)
+}
+
+// SearchWithTotal is Search also returning how many documents match in
+// all, regardless of Limit – enough to render "page 3 of 57".
+func (r *Repository) SearchWithTotal(
+	ctx context.Context,
+	where q.Expr,
+	opts ...Opt,
+) ([]*scan.Doc, int, error) {
+
+	where = r.scoped(where, nil, opts)
+	sb := r.newSearch(where).
+		Using(r.exec)
+	for _, opt := range opts {
+		opt.applySearch(sb)
+	}
+	res, err := sb.RunResult(ctx)
+	if err != nil && !errors.Is(err, q.ErrPartialResults) {
+		return nil, 0, err
+	}
+	if r.json {
+		if jerr := expandJSON(res.Hits, nil); jerr != nil {
+			return nil, 0, jerr
+		}
+	}
+	docs := make([]*scan.Doc, len(res.Hits))
+	for i, h := range res.Hits {
+		docs[i] = scan.NewDoc(h.Fields)
+	}
+	return docs, res.Total, err
+}
+
+// SearchKeys is Search returning only the keys of the matching documents
+// (NOCONTENT), for callers that fetch or cache the documents themselves.
+// Select, Highlight and other payload options have no effect.
+func (r *Repository) SearchKeys(
+	ctx context.Context,
+	where q.Expr,
+	opts ...Opt,
+) ([]string, error) {
 
-	sb := q.NewSearch(r.index).
-		Where(where).
+	where = r.scoped(where, nil, opts)
+	sb := r.newSearch(where).
 		Using(r.exec)
+	for _, opt := range opts {
+		opt.applySearch(sb)
+	}
+	return sb.Keys(ctx)
+}
 
+// Count returns how many documents match where, without fetching any
+// (NOCONTENT LIMIT 0 0).  Only Unscoped is honoured among opts.
+func (r *Repository) Count(ctx context.Context, where q.Expr, opts ...Opt) (int, error) {
+	res, err := r.newSearch(r.scoped(where, nil, opts)).
+		NoContent().
+		Limit(0, 0).
+		Using(r.exec).
+		RunResult(ctx)
+	return res.Total, err
+}
+
+// ExistsWhere reports whether any document matches where, fetching at most
+// one key (NOCONTENT LIMIT 0 1).  Only Unscoped is honoured among opts.
+func (r *Repository) ExistsWhere(ctx context.Context, where q.Expr, opts ...Opt) (bool, error) {
+	keys, err := r.pageKeys(ctx, r.scoped(where, nil, opts), 0, 1)
+	return len(keys) > 0, err
+}
+
+// SearchIter yields every hit of where, fetching batch at a time so no
+// result is lost to LIMIT.  Sorting by a NUMERIC field that is also
+// selected pages by key (search-after, stable under concurrent writes);
+// otherwise pages advance by OFFSET.  Limit options are overridden.
+func (r *Repository) SearchIter(
+	ctx context.Context,
+	where q.Expr,
+	batch int,
+	opts ...Opt,
+) iter.Seq2[*scan.Doc, error] {
+
+	where = r.scoped(where, nil, opts)
+	sb := r.newSearch(where).
+		Using(r.exec)
 	for _, opt := range opts {
 		opt.applySearch(sb)
 	}
-	return sb.Run(ctx)
+	sb.Limit(0, batch)
+
+	return func(yield func(*scan.Doc, error) bool) {
+		var partial error
+		var cur q.Cursor
+		for {
+			rows, err := sb.Run(ctx)
+			if errors.Is(err, q.ErrPartialResults) {
+				partial, err = err, nil
+			}
+			if err == nil {
+				err = r.expandRows(rows)
+			}
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			for _, row := range rows {
+				if !yield(scan.NewDoc(row), nil) {
+					return
+				}
+			}
+			c, ok := sb.Next(rows)
+			if !ok || c == cur { // a cursor that does not advance would re-read the page
+				break
+			}
+			cur = c
+			sb.After(c)
+		}
+		if partial != nil {
+			yield(nil, partial)
+		}
+	}
+}
+
+// SearchAll collects SearchIter into a slice.
+func (r *Repository) SearchAll(
+	ctx context.Context,
+	where q.Expr,
+	batch int,
+	opts ...Opt,
+) ([]*scan.Doc, error) {
+	var out []*scan.Doc
+	for doc, err := range r.SearchIter(ctx, where, batch, opts...) {
+		if err != nil {
+			return out, err
+		}
+		out = append(out, doc)
+	}
+	return out, nil
 }
 
 // -------------------------------------------------------------------
@@ -56,19 +323,72 @@ func (r *Repository) Search(
 // -------------------------------------------------------------------
 
 // Aggregate runs FT.AGGREGATE.  Caller supplies group-by fields and optional
-// reducers.  Result is a slice of map[string]string for maximum flexibility.
+// reducers.  Rows come back as scan.Docs (scan.Maps unwraps them, e.g. for
+// scan.Pivot).
 func (r *Repository) Aggregate(
 	ctx context.Context,
 	where q.Expr,
 	opts ...Opt,
-) ([]map[string]string, error) {
+) ([]*scan.Doc, error) {
 
-	ab := q.NewAggregate(r.index).
-		Where(where).
+	where = r.scoped(where, nil, opts)
+	ab := r.newAggregate(where).
 		Using(r.exec)
 
 	for _, opt := range opts {
 		opt.applyAgg(ab)
 	}
-	return ab.Run(ctx)
+	rows, err := ab.Run(ctx)
+	if err == nil {
+		err = r.expandRows(rows)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return scan.ToDocs(rows), nil
+}
+
+// AggregateStream is Aggregate for results too large for one reply: rows
+// are read through an FT.AGGREGATE cursor, batch rows per round trip, and
+// yielded as they arrive.  Breaking out of the loop releases the cursor.
+//
+//	for doc, err := range repo.AggregateStream(ctx, where, 500, repository.Group(q.By("sku"))) {
+//	    …
+//	}
+func (r *Repository) AggregateStream(
+	ctx context.Context,
+	where q.Expr,
+	batch int,
+	opts ...Opt,
+) iter.Seq2[*scan.Doc, error] {
+	return func(yield func(*scan.Doc, error) bool) {
+		for row, err := range r.aggStream(ctx, where, batch, opts) {
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if !yield(scan.NewDoc(row), nil) {
+				return
+			}
+		}
+	}
+}
+
+// aggStream is the row stream behind AggregateStream and AggregateIter.
+func (r *Repository) aggStream(
+	ctx context.Context,
+	where q.Expr,
+	batch int,
+	opts []Opt,
+) iter.Seq2[map[string]string, error] {
+
+	where = r.scoped(where, nil, opts)
+	ab := r.newAggregate(where).
+		Using(r.exec).
+		WithCursor(batch)
+
+	for _, opt := range opts {
+		opt.applyAgg(ab)
+	}
+	return ab.Stream(ctx)
 }
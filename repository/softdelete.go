@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	q "github.com/manojoshi/redisorm/query"
+	"github.com/manojoshi/redisorm/scan"
+)
+
+// Soft delete: a model with a NUMERIC field tagged SOFTDELETE
+//
+//	type Cart struct {
+//	    ID        string    `redisorm:"@id,PK"`
+//	    DeletedAt time.Time `redisorm:"@deleted_at,NUMERIC,SOFTDELETE"`
+//	}
+//
+// is never DELeted by Delete: the field is stamped with the current time
+// instead (unix seconds for integer fields, the field's codec for
+// time.Time).  Searches and aggregations hide stamped documents unless
+// given Unscoped(); PurgeDeleted removes old tombstones for good.  The
+// model is the entity passed to Delete or Find's T, else – for IDs, maps
+// and *scan.Doc – the ForModel one.
+
+// Unscoped includes soft-deleted documents in a search or aggregation.
+func Unscoped() Opt {
+	return optFunc{
+		load: func(c *loadCfg) { c.unscoped = true },
+	}
+}
+
+// softField returns the SOFTDELETE field of model.  A nil model, or one
+// without redisorm fields (map[string]string, *scan.Doc), stands for the
+// repository's ForModel type.
+func (r *Repository) softField(model reflect.Type) (scan.Field, bool) {
+	if model == nil || len(scan.Fields(model)) == 0 {
+		model = reflect.TypeOf(r.model)
+	}
+	if model == nil {
+		return scan.Field{}, false
+	}
+	for _, f := range scan.Fields(model) {
+		if hasAttr(f.Attrs, "SOFTDELETE") {
+			return f, true
+		}
+	}
+	return scan.Field{}, false
+}
+
+// scoped narrows where to live documents when the model soft-deletes and
+// opts do not include Unscoped.  Documents written before the field
+// existed carry no value and count as live.
+func (r *Repository) scoped(where q.Expr, model reflect.Type, opts []Opt) q.Expr {
+	f, ok := r.softField(model)
+	if !ok {
+		return where
+	}
+	cfg := &loadCfg{}
+	for _, o := range opts {
+		o.applyLoad(cfg)
+	}
+	if cfg.unscoped {
+		return where
+	}
+	live := q.Not(q.Gt(f.Name, 0))
+	if where == nil || where == q.MatchAll() {
+		return live
+	}
+	return q.And(where, live)
+}
+
+// softDelete stamps the SOFTDELETE field of the document at key.
+func (r *Repository) softDelete(ctx context.Context, key string, model reflect.Type, f scan.Field, opts []WriteOpt) error {
+	stamp, err := softStamp(f, time.Now())
+	if err != nil {
+		return err
+	}
+	if !r.json {
+		return r.update(ctx, key, model, opts, len(key)+len(stamp), hset(key, map[string]any{f.Name: stamp}))
+	}
+	return r.updateJSON(ctx, key, model, map[string]any{"$." + strings.Join(f.JSON, "."): json.Number(stamp)}, opts)
+}
+
+// softStamp is t as stored in the SOFTDELETE field f.
+func softStamp(f scan.Field, t time.Time) (string, error) {
+	ft := f.Struct.Type
+	if ft.Kind() == reflect.Pointer {
+		ft = ft.Elem()
+	}
+	if ft == reflect.TypeOf(time.Time{}) {
+		if c, ok := scan.CodecForField(f.Struct); ok {
+			return c.Encode(reflect.ValueOf(t))
+		}
+	}
+	return strconv.FormatInt(t.Unix(), 10), nil
+}
+
+// PurgeDeleted physically deletes the documents soft-deleted more than
+// olderThan ago and returns how many were removed.  The repository needs a
+// soft-deleting ForModel type.
+func (r *Repository) PurgeDeleted(ctx context.Context, olderThan time.Duration, opts ...BulkOpt) (int, error) {
+	f, ok := r.softField(nil)
+	if !ok {
+		return 0, fmt.Errorf("repository: PurgeDeleted needs a ForModel repository with a SOFTDELETE field")
+	}
+	cutoff, err := softStamp(f, time.Now().Add(-olderThan))
+	if err != nil {
+		return 0, err
+	}
+	n, err := strconv.ParseInt(cutoff, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	return r.deleteWhere(ctx, q.Between(f.Name, 1, n), newBulkCfg(opts))
+}
@@ -0,0 +1,206 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/manojoshi/redisorm/driver"
+	q "github.com/manojoshi/redisorm/query"
+)
+
+// Corrections maps each misspelled query term to the suggestion a
+// spell-corrected retry used instead.
+type Corrections map[string]string
+
+// SpellCorrect enables the "did you mean" flow on Search and Find: when the
+// query returns no hits, FT.SPELLCHECK is run on it, the Text / Match terms
+// are replaced by the top suggestions and the search is retried once.  The
+// terms that were corrected are stored in *dst (left empty when no retry
+// happened), so the caller can show "showing results for …".
+func SpellCorrect(dst *Corrections) Opt {
+	return optFunc{
+		load: func(c *loadCfg) { c.corrections = dst },
+	}
+}
+
+// SpellSuggestion is one candidate for a misspelled term.
+type SpellSuggestion struct {
+	Term  string
+	Score float64 // 0..1, relative frequency in the index (or dictionary)
+}
+
+// Misspelling is a query term FT.SPELLCHECK flagged, with its candidates
+// best first.  Suggestions is empty when nothing close enough is known.
+type Misspelling struct {
+	Term        string
+	Suggestions []SpellSuggestion
+}
+
+// SpellOpt tunes SpellCheck.
+type SpellOpt func(*spellCfg)
+
+type spellCfg struct {
+	distance int
+	terms    []interface{} // TERMS INCLUDE|EXCLUDE dict …
+}
+
+// SpellDistance sets the maximum Levenshtein distance of suggestions,
+// 1 (default) to 4.
+func SpellDistance(n int) SpellOpt { return func(c *spellCfg) { c.distance = n } }
+
+// IncludeDict also suggests the terms of a custom dictionary (see DictAdd).
+func IncludeDict(dict string) SpellOpt {
+	return func(c *spellCfg) { c.terms = append(c.terms, "TERMS", "INCLUDE", dict) }
+}
+
+// ExcludeDict never flags the terms of a custom dictionary as misspelled:
+// brand names, jargon, ….
+func ExcludeDict(dict string) SpellOpt {
+	return func(c *spellCfg) { c.terms = append(c.terms, "TERMS", "EXCLUDE", dict) }
+}
+
+// SpellCheck runs FT.SPELLCHECK on where against the index and returns the
+// misspelled terms in query order, for building "did you mean" prompts.
+// (SpellCorrect does the common case – retry with the best suggestions –
+// inside Search.)
+//
+//	miss, err := repo.SpellCheck(ctx, q.Text("shiped ordr"), repository.SpellDistance(2))
+func (r *Repository) SpellCheck(ctx context.Context, where q.Expr, opts ...SpellOpt) ([]Misspelling, error) {
+	cfg := &spellCfg{}
+	for _, o := range opts {
+		o(cfg)
+	}
+	args := []interface{}{"FT.SPELLCHECK", r.index, q.Compile(where)}
+	if cfg.distance > 0 {
+		args = append(args, "DISTANCE", cfg.distance)
+	}
+	args = append(args, cfg.terms...)
+	raw, err := r.exec.Do(ctx, args...)
+	if err != nil {
+		return nil, driver.Wrap(args, err)
+	}
+	return parseSpellcheck(raw)
+}
+
+// parseSpellcheck decodes FT.SPELLCHECK, RESP-2 or RESP-3.
+func parseSpellcheck(raw any) ([]Misspelling, error) {
+	var out []Misspelling
+	switch v := raw.(type) {
+	case []interface{}: // RESP-2: [["TERM", term, [[score, suggestion], …]], …]
+		for _, t := range v {
+			entry, ok := t.([]interface{})
+			if !ok || len(entry) != 3 {
+				return nil, fmt.Errorf("repository: unexpected FT.SPELLCHECK entry %v", t)
+			}
+			m := Misspelling{Term: fmt.Sprint(entry[1])}
+			sugs, _ := entry[2].([]interface{})
+			for _, s := range sugs {
+				pair, _ := s.([]interface{})
+				if len(pair) != 2 {
+					continue
+				}
+				score, _ := strconv.ParseFloat(fmt.Sprint(pair[0]), 64)
+				m.Suggestions = append(m.Suggestions, SpellSuggestion{Term: fmt.Sprint(pair[1]), Score: score})
+			}
+			out = append(out, m)
+		}
+	case map[interface{}]interface{}: // RESP-3: {results: {term: [{suggestion: score}, …]}}
+		results, _ := v["results"].(map[interface{}]interface{})
+		for term, sugs := range results {
+			m := Misspelling{Term: fmt.Sprint(term)}
+			list, _ := sugs.([]interface{})
+			for _, s := range list {
+				pairs, _ := s.(map[interface{}]interface{})
+				for sug, score := range pairs {
+					f, _ := strconv.ParseFloat(fmt.Sprint(score), 64)
+					m.Suggestions = append(m.Suggestions, SpellSuggestion{Term: fmt.Sprint(sug), Score: f})
+				}
+			}
+			out = append(out, m)
+		}
+		// maps lose query order; keep the result stable at least
+		slices.SortFunc(out, func(a, b Misspelling) int { return strings.Compare(a.Term, b.Term) })
+	default:
+		return nil, fmt.Errorf("repository: unexpected FT.SPELLCHECK reply %T", raw)
+	}
+	for _, m := range out {
+		slices.SortStableFunc(m.Suggestions, func(a, b SpellSuggestion) int { return cmp.Compare(b.Score, a.Score) })
+	}
+	return out, nil
+}
+
+// spellcheck returns the top suggestion for every misspelled term of where.
+func (r *Repository) spellcheck(ctx context.Context, where q.Expr) (Corrections, error) {
+	miss, err := r.SpellCheck(ctx, where)
+	if err != nil {
+		return nil, err
+	}
+	out := Corrections{}
+	for _, m := range miss {
+		if len(m.Suggestions) > 0 {
+			out[m.Term] = m.Suggestions[0].Term
+		}
+	}
+	return out, nil
+}
+
+// DictAdd adds terms to the custom spellcheck dictionary dict (FT.DICTADD),
+// returning how many were new.
+func (r *Repository) DictAdd(ctx context.Context, dict string, terms ...string) (int, error) {
+	return r.dictCmd(ctx, "FT.DICTADD", dict, terms)
+}
+
+// DictDel removes terms from dict (FT.DICTDEL), returning how many were
+// present.
+func (r *Repository) DictDel(ctx context.Context, dict string, terms ...string) (int, error) {
+	return r.dictCmd(ctx, "FT.DICTDEL", dict, terms)
+}
+
+// DictDump lists the terms of dict (FT.DICTDUMP).
+func (r *Repository) DictDump(ctx context.Context, dict string) ([]string, error) {
+	args := []interface{}{"FT.DICTDUMP", dict}
+	raw, err := r.exec.Do(ctx, args...)
+	if err != nil {
+		return nil, driver.Wrap(args, err)
+	}
+	list, ok := raw.([]interface{}) // RESP-3 sets arrive as arrays too
+	if !ok {
+		return nil, fmt.Errorf("repository: unexpected FT.DICTDUMP reply %T", raw)
+	}
+	out := make([]string, len(list))
+	for i, t := range list {
+		out[i] = fmt.Sprint(t)
+	}
+	return out, nil
+}
+
+func (r *Repository) dictCmd(ctx context.Context, verb, dict string, terms []string) (int, error) {
+	args := []interface{}{verb, dict}
+	for _, t := range terms {
+		args = append(args, t)
+	}
+	raw, err := r.exec.Do(ctx, args...)
+	if err != nil {
+		return 0, driver.Wrap(args, err)
+	}
+	n, _ := raw.(int64)
+	return int(n), nil
+}
+
+// corrected runs the spell check for an empty result and returns the
+// rewritten query, or nil when there is nothing to correct.
+func (r *Repository) corrected(ctx context.Context, where q.Expr, cfg *loadCfg) (q.Expr, error) {
+	if cfg.corrections == nil || where == nil || cfg.dryRun != nil {
+		return nil, nil
+	}
+	corr, err := r.spellcheck(ctx, where)
+	if err != nil || len(corr) == 0 {
+		return nil, err
+	}
+	*cfg.corrections = corr
+	return q.Correct(where, corr), nil
+}
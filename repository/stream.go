@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"reflect"
+
+	q "github.com/manojoshi/redisorm/query"
+	"github.com/manojoshi/redisorm/scan"
+)
+
+// FindIter is Find for result sets of any size: hits are fetched batch at a
+// time, decoded into T and yielded one by one, so memory stays constant no
+// matter how many documents match.  Paging works as for SearchIter
+// (search-after when sorted by a selected NUMERIC field); Preload and the
+// AfterLoad hooks run per batch.  Breaking out of the loop stops fetching;
+// an error is yielded once, last.
+//
+//	for o, err := range repository.FindIter[Order](ctx, repo, where, 1000,
+//	    repository.SortAsc("created_ts")) {
+//	    if err != nil {
+//	        return err
+//	    }
+//	    …
+//	}
+func FindIter[T any](ctx context.Context, r *Repository, where q.Expr, batch int, opts ...Opt) iter.Seq2[T, error] {
+	model := reflect.TypeOf((*T)(nil)).Elem()
+	sb := r.newSearch(r.scoped(where, model, opts)).
+		Using(r.exec)
+	cfg := &loadCfg{}
+	for _, o := range opts {
+		o.applySearch(sb)
+		o.applyLoad(cfg)
+	}
+	sb.Limit(0, batch)
+
+	return func(yield func(T, error) bool) {
+		var zero T
+		var partial error
+		var cur q.Cursor
+		for {
+			res, err := sb.RunResult(ctx)
+			if errors.Is(err, q.ErrPartialResults) {
+				partial, err = err, nil
+			}
+			if err == nil && r.json {
+				err = expandJSON(res.Hits, model)
+			}
+			var out []T
+			if err == nil {
+				out, err = scan.Docs[T](res.Hits)
+			}
+			if err == nil {
+				err = loaded(ctx, r, out, cfg)
+			}
+			if err != nil {
+				yield(zero, err)
+				return
+			}
+			for _, v := range out {
+				if !yield(v, nil) {
+					return
+				}
+			}
+			rows := make([]map[string]string, len(res.Hits))
+			for i, h := range res.Hits {
+				rows[i] = h.Fields
+			}
+			c, ok := sb.Next(rows)
+			if !ok || c == cur { // as in SearchIter
+				break
+			}
+			cur = c
+			sb.After(c)
+		}
+		if partial != nil {
+			yield(zero, partial)
+		}
+	}
+}
+
+// AggregateIter is AggregateStream decoding every row into T (a struct
+// tagged with the group keys and reducer aliases, or map[string]string).
+//
+//	type skuQty struct {
+//	    SKU string `redisorm:"@sku"`
+//	    Qty int    `redisorm:"@total"`
+//	}
+//	for row, err := range repository.AggregateIter[skuQty](ctx, repo, where, 500,
+//	    repository.Group(q.By("sku")), repository.Sum("qty", "total")) {
+//	    …
+//	}
+func AggregateIter[T any](ctx context.Context, r *Repository, where q.Expr, batch int, opts ...Opt) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+		for row, err := range r.aggStream(ctx, where, batch, opts) {
+			var out []T
+			if err == nil {
+				out, err = scan.Docs[T]([]scan.Hit{{Fields: row}})
+			}
+			if err != nil {
+				yield(zero, err)
+				return
+			}
+			if !yield(out[0], nil) {
+				return
+			}
+		}
+	}
+}
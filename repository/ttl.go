@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/manojoshi/redisorm/driver"
+)
+
+// Expire sets the time to live of an entity (given as for Delete) and
+// reports whether it exists.  See WithTTL to set it while saving.
+func (r *Repository) Expire(ctx context.Context, idOrEntity any, d time.Duration) (bool, error) {
+	return r.keyCmd(ctx, idOrEntity, "PEXPIRE", max(d.Milliseconds(), 1))
+}
+
+// Persist removes an entity's time to live, reporting whether it had one.
+func (r *Repository) Persist(ctx context.Context, idOrEntity any) (bool, error) {
+	return r.keyCmd(ctx, idOrEntity, "PERSIST")
+}
+
+// TTL returns the remaining time to live of an entity: 0 when it has no
+// expiry, ErrNotFound when it does not exist.
+func (r *Repository) TTL(ctx context.Context, idOrEntity any) (time.Duration, error) {
+	key, err := r.idKey(idOrEntity, nil)
+	if err != nil {
+		return 0, err
+	}
+	args := []interface{}{"PTTL", key}
+	raw, err := r.exec.Do(ctx, args...)
+	if err != nil {
+		return 0, driver.Wrap(args, err)
+	}
+	ms, _ := raw.(int64)
+	switch ms {
+	case -2:
+		return 0, fmt.Errorf("repository: %s: %w", key, ErrNotFound)
+	case -1:
+		return 0, nil
+	}
+	return time.Duration(ms) * time.Millisecond, nil
+}
+
+// keyCmd runs verb key extra… and reports an integer reply of 1 as true.
+func (r *Repository) keyCmd(ctx context.Context, idOrEntity any, verb string, extra ...interface{}) (bool, error) {
+	key, err := r.idKey(idOrEntity, nil)
+	if err != nil {
+		return false, err
+	}
+	args := append([]interface{}{verb, key}, extra...)
+	raw, err := r.exec.Do(ctx, args...)
+	if err != nil {
+		return false, driver.Wrap(args, err)
+	}
+	n, _ := raw.(int64)
+	return n == 1, nil
+}
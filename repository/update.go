@@ -0,0 +1,218 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/manojoshi/redisorm/index"
+	"github.com/manojoshi/redisorm/scan"
+)
+
+// Update sets only the given fields of the entity with primary key id (or
+// of the entity itself, as for Delete), leaving the rest of the document
+// alone – bump a counter or flip a status without rewriting the hash:
+//
+//	err := repo.Update(ctx, "42", map[string]any{"status": "SHIPPED"})
+//
+// Field names are the redisorm names.  On a ForModel repository a value of
+// the named field's Go type is encoded as Save would encode it (time.Time
+// as unix seconds, big.Rat with its SHADOW sibling, …); other values are
+// written as given.  On AsJSON repositories each name is set at its
+// JSONPath ($.<name> without a model).
+// Update does not check that the entity exists: on a hash repository a
+// missing key becomes a partial hash.  SkipSeen does not apply to updates.
+// On VERSION models the fields and a version bump are written by one
+// script, so a Save of an older copy fails with ErrStaleVersion.
+func (r *Repository) Update(ctx context.Context, id any, fields map[string]any, opts ...WriteOpt) error {
+	key, err := r.idKey(id, nil)
+	if err != nil {
+		return err
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+	vals, err := r.changeValues(fields)
+	if err != nil {
+		return err
+	}
+	model := reflect.TypeOf(id)
+	if model != nil && model.Kind() == reflect.Pointer {
+		model = model.Elem()
+	}
+	if model != nil && model.Kind() != reflect.Struct {
+		model = nil
+	}
+	if !r.json {
+		return r.update(ctx, key, model, opts, payloadSize(key, vals), hset(key, vals))
+	}
+	return r.updateJSON(ctx, key, model, vals, opts)
+}
+
+// changeValues encodes the Update changes fields, keyed by hash field or,
+// on AsJSON repositories, by JSONPath.
+func (r *Repository) changeValues(fields map[string]any) (map[string]any, error) {
+	known := map[string]scan.Field{}
+	if r.model != nil {
+		for _, f := range scan.Fields(reflect.TypeOf(r.model)) {
+			known[f.Name] = f
+		}
+	}
+	vals := make(map[string]any, len(fields))
+	for name, v := range fields {
+		f, ok := known[name]
+		path := name
+		switch {
+		case r.json && ok:
+			path = "$." + strings.Join(f.JSON, ".")
+		case r.json:
+			path = "$." + name
+		}
+		if !ok {
+			vals[path] = v
+			continue
+		}
+		enc, shadow, err := changeValue(f, v, r.json)
+		if err != nil {
+			return nil, err
+		}
+		vals[path] = enc
+		if shadow != nil {
+			vals[path+index.ShadowSuffix] = shadow
+		}
+	}
+	return vals, nil
+}
+
+// changeValue encodes an Update value for the model field f the way Save
+// does, when v has the field's type and the type has a codec; shadow is
+// the SHADOW sibling's value, if the field has one.  Anything else is
+// returned as given.
+func changeValue(f scan.Field, v any, asJSON bool) (enc, shadow any, err error) {
+	rv := reflect.ValueOf(v)
+	codec, ok := scan.CodecForField(f.Struct)
+	if !ok || !rv.IsValid() || !rv.Type().AssignableTo(f.Struct.Type) {
+		return v, nil, nil
+	}
+	fv := reflect.New(f.Struct.Type).Elem()
+	fv.Set(rv)
+	s, err := codec.Encode(fv)
+	if err != nil {
+		return nil, nil, fmt.Errorf("repository: field %s: %w", f.Name, err)
+	}
+	enc = s
+	nc, numeric := codec.(scan.NumericCodec)
+	if _, perr := strconv.ParseFloat(s, 64); asJSON && numeric && perr == nil {
+		enc = json.Number(s) // as jsonDoc writes it
+	}
+	if numeric && hasAttr(f.Attrs, "SHADOW") {
+		n, err := nc.Float64(fv)
+		if err != nil {
+			return nil, nil, fmt.Errorf("repository: field %s: %w", f.Name, err)
+		}
+		shadow = n
+	}
+	return enc, shadow, nil
+}
+
+// UpdateModel is Update taking the values from entity: only the fields
+// named in fieldMask (redisorm names, "status", "audit_updated_ts") are
+// written, encoded exactly as Save would encode them, SHADOW siblings
+// included.  The key comes from entity as for Save.
+//
+//	o.Status, o.UpdatedAt = "SHIPPED", time.Now()
+//	err := repo.UpdateModel(ctx, &o, "status", "updated_at")
+func (r *Repository) UpdateModel(ctx context.Context, entity any, fieldMask ...string) error {
+	return r.UpdateModelWith(ctx, entity, fieldMask)
+}
+
+// UpdateModelWith is UpdateModel with write options, applied as for Update.
+//
+//	err := repo.UpdateModelWith(ctx, &o, []string{"status"}, repository.WithReplicas(1, time.Second))
+func (r *Repository) UpdateModelWith(ctx context.Context, entity any, fieldMask []string, opts ...WriteOpt) error {
+	key, err := r.Key(entity)
+	if err != nil {
+		return err
+	}
+	if len(fieldMask) == 0 {
+		return nil
+	}
+	known := map[string]scan.Field{}
+	for _, f := range scan.Fields(reflect.TypeOf(entity)) {
+		known[f.Name] = f
+	}
+	for _, name := range fieldMask {
+		if _, ok := known[name]; !ok || scan.MetaField(name) {
+			return fmt.Errorf("repository: %T has no field %q to update", entity, name)
+		}
+	}
+
+	if !r.json {
+		all, err := structToMap(entity)
+		if err != nil {
+			return err
+		}
+		vals := make(map[string]any, len(fieldMask))
+		for _, name := range fieldMask {
+			vals[name] = all[name]
+			if v, ok := all[name+index.ShadowSuffix]; ok && hasAttr(known[name].Attrs, "SHADOW") {
+				vals[name+index.ShadowSuffix] = v
+			}
+		}
+		return r.update(ctx, key, reflect.TypeOf(entity), opts, payloadSize(key, vals), hset(key, vals))
+	}
+
+	blob, err := jsonDoc(entity)
+	if err != nil {
+		return err
+	}
+	var doc map[string]any
+	if err := json.Unmarshal(blob, &doc); err != nil {
+		return err
+	}
+	paths := map[string]any{}
+	for _, name := range fieldMask {
+		f := known[name]
+		leaf := f.JSON[len(f.JSON)-1]
+		obj := doc
+		for _, p := range f.JSON[:len(f.JSON)-1] {
+			obj, _ = obj[p].(map[string]any)
+		}
+		prefix := "$." + strings.Join(f.JSON[:len(f.JSON)-1], ".")
+		if len(f.JSON) > 1 {
+			prefix += "."
+		}
+		paths[prefix+leaf] = obj[leaf]
+		if v, ok := obj[leaf+index.ShadowSuffix]; ok {
+			paths[prefix+leaf+index.ShadowSuffix] = v
+		}
+	}
+	return r.updateJSON(ctx, key, reflect.TypeOf(entity), paths, opts)
+}
+
+// updateJSON sets each JSONPath of an AsJSON document, in one pipeline.
+func (r *Repository) updateJSON(ctx context.Context, key string, model reflect.Type, paths map[string]any, opts []WriteOpt) error {
+	cmds := make([][]interface{}, 0, len(paths))
+	size := len(key)
+	for path, v := range paths {
+		b, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Errorf("repository: %s: %w", path, err)
+		}
+		cmds = append(cmds, []interface{}{"JSON.SET", key, path, string(b)})
+		size += len(path) + len(b)
+	}
+	return r.update(ctx, key, model, opts, size, cmds...)
+}
+
+// update writes a partial document through writeDoc.  The SkipSeen filter
+// is bypassed: it records that a key was written at all, and skipping later
+// changes to it would lose them.
+func (r *Repository) update(ctx context.Context, key string, model reflect.Type, opts []WriteOpt, size int, cmds ...[]interface{}) error {
+	cfg := newWriteCfg(opts)
+	cfg.seenFilter = ""
+	return writeDoc(ctx, r.exec, key, nil, cfg, true, size, r.versioned(model, key, cmds)...)
+}
@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// replaceHashScript: KEYS[1] key; ARGV field/value pairs.  The hash is
+// replaced in one step, so readers never see it missing or half-written.
+const replaceHashScript = `
+redis.call('DEL', KEYS[1])
+return redis.call('HSET', KEYS[1], unpack(ARGV))
+`
+
+// Upsert writes entity under the key its repository's key scheme derives
+// from it (typically a template over the PK, "order:{pk}"), creating the
+// document or replacing it as a whole: unlike Save, fields stored earlier
+// but no longer produced by the model are dropped.  ID generation and
+// hooks run as for Save; VERSION models are saved with their version
+// check, as by Save.
+//
+//	repo := repository.New("order_idx", conn,
+//	    repository.WithKeyTemplate("order:{pk}"))
+//	err := repo.Upsert(ctx, &order)
+func (r *Repository) Upsert(ctx context.Context, entity any, opts ...WriteOpt) error {
+	key, err := r.prepareUpsert(ctx, entity)
+	if err != nil {
+		return err
+	}
+	if vf, ok := versionField(reflect.TypeOf(entity)); ok {
+		err = r.saveVersioned(ctx, key, entity, vf, opts)
+	} else {
+		var cmd []interface{}
+		var size int
+		if cmd, size, err = r.upsertCmd(key, entity); err == nil {
+			err = writeDoc(ctx, r.exec, key, entity, newWriteCfg(opts), true, size, cmd)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	return r.afterSave(ctx, entity)
+}
+
+// MultiUpsert is Upsert for many entities, written in pipelined chunks the
+// way Repo.LoadBulk writes records (LoadChunk, LoadWorkers and the other
+// write options apply likewise).  Entities whose key, hooks or write fail
+// are listed in the report; the rest are stored.  VERSION models are not
+// supported: their writes must be checked one by one, use Save.
+//
+//	rep, err := repo.MultiUpsert(ctx, orders, repository.LoadChunk(1000))
+func (r *Repository) MultiUpsert(ctx context.Context, entities []any, opts ...WriteOpt) (*LoadReport, error) {
+	cfg := newWriteCfg(opts)
+	rep := &LoadReport{}
+	recs := make([]loadRecord, 0, len(entities))
+	for i, e := range entities {
+		if _, ok := versionField(reflect.TypeOf(e)); ok {
+			return nil, fmt.Errorf("repository: MultiUpsert cannot check the VERSION of %T; use Save", e)
+		}
+		key, err := r.prepareUpsert(ctx, e)
+		if err != nil {
+			rep.Errors = append(rep.Errors, RecordError{Index: i, Key: key, Err: err})
+			continue
+		}
+		cmd, size, err := r.upsertCmd(key, e)
+		if err != nil {
+			rep.Errors = append(rep.Errors, RecordError{Index: i, Key: key, Err: err})
+			continue
+		}
+		recs = append(recs, loadRecord{index: i, key: key, cmd: cmd, size: size, rec: e})
+	}
+	rep, _ = loadRecords(ctx, r.exec, rep, recs, len(entities), cfg) // error re-derived below
+
+	// AfterSave runs for the entities that were stored
+	bad := make(map[int]bool, len(rep.Errors))
+	for _, e := range rep.Errors {
+		bad[e.Index] = true
+	}
+	for _, rc := range recs {
+		if bad[rc.index] {
+			continue
+		}
+		if hookErr := r.afterSave(ctx, rc.rec); hookErr != nil {
+			rep.Errors = append(rep.Errors, RecordError{Index: rc.index, Key: rc.key, Err: hookErr})
+			rep.Written--
+		}
+	}
+	return rep, rep.err(len(entities))
+}
+
+// prepareUpsert assigns the ID of entity, runs BeforeSave and returns its
+// key.
+func (r *Repository) prepareUpsert(ctx context.Context, entity any) (string, error) {
+	if err := r.assignID(entity); err != nil {
+		return "", err
+	}
+	if err := r.beforeSave(ctx, entity); err != nil {
+		return "", err
+	}
+	return r.Key(entity)
+}
+
+// upsertCmd is the write replacing the document at key with entity, and
+// its payload size.
+func (r *Repository) upsertCmd(key string, entity any) ([]interface{}, int, error) {
+	if r.json {
+		doc, err := jsonDoc(entity)
+		if err != nil {
+			return nil, 0, err
+		}
+		return []interface{}{"JSON.SET", key, "$", string(doc)}, len(key) + len(doc), nil
+	}
+	vals, err := structToMap(entity)
+	if err != nil {
+		return nil, 0, err
+	}
+	args := append([]interface{}{"EVAL", replaceHashScript, 1}, hset(key, vals)[1:]...)
+	return args, payloadSize(key, vals), nil
+}
@@ -0,0 +1,174 @@
+package repository
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/manojoshi/redisorm/scan"
+)
+
+// Optimistic concurrency: a model with a field tagged VERSION
+//
+//	type Account struct {
+//	    ID      string `redisorm:"@id,PK"`
+//	    Balance int64  `redisorm:"@balance,NUMERIC"`
+//	    Version int64  `redisorm:"@version,NUMERIC,VERSION"`
+//	}
+//
+// is saved by a script that compares the stored version with the entity's
+// and writes only when they are equal, storing version+1.  A concurrent
+// writer that got there first makes Save fail with ErrStaleVersion; reload
+// and retry.  A new entity has version 0.  Partial writes (Update, Incr,
+// UpdateWhere, soft deletes) are not checked but bump the version in the
+// same script, so they too make older copies stale.
+
+// staleMarker prefixes the script error that becomes ErrStaleVersion.
+const staleMarker = "STALE_VERSION"
+
+// versionHashScript: KEYS[1] key; ARGV[1] version field, ARGV[2] expected
+// version, ARGV[3…] field/value pairs (version already bumped).
+const versionHashScript = `
+local cur = redis.call('HGET', KEYS[1], ARGV[1]) or '0'
+if cur ~= ARGV[2] then
+  return redis.error_reply('` + staleMarker + ` ' .. cur)
+end
+redis.call('HSET', KEYS[1], unpack(ARGV, 3))
+return 1
+`
+
+// versionJSONScript: KEYS[1] key; ARGV[1] JSONPath of the version, ARGV[2]
+// expected version, ARGV[3] the document (version already bumped).
+const versionJSONScript = `
+local cur = '0'
+if redis.call('EXISTS', KEYS[1]) == 1 then
+  local v = cjson.decode(redis.call('JSON.GET', KEYS[1], ARGV[1]))[1]
+  if v ~= nil then cur = string.format('%d', v) end
+end
+if cur ~= ARGV[2] then
+  return redis.error_reply('` + staleMarker + ` ' .. cur)
+end
+redis.call('JSON.SET', KEYS[1], '$', ARGV[3])
+return 1
+`
+
+// versionedWrite runs a partial write atomically.  KEYS[1] key; ARGV[1]
+// "1" if the key must already exist, ARGV[2] the number of commands, then
+// each command as its word count and words.  The reply is the first
+// command's, or nil when the key had to exist and does not.
+const versionedWrite = `
+if ARGV[1] == '1' and redis.call('EXISTS', KEYS[1]) == 0 then
+  return nil
+end
+local i, first = 3, nil
+for c = 1, tonumber(ARGV[2]) do
+  local n = tonumber(ARGV[i])
+  local reply = redis.call(unpack(ARGV, i + 1, i + n))
+  if c == 1 then first = reply end
+  i = i + n + 1
+end
+return first
+`
+
+// versioned wraps the partial write cmds on key (Update, UpdateModel,
+// Incr, UpdateWhere, soft deletes) into one script that also increments
+// the VERSION field of model (nil: the ForModel type), so a Save holding
+// an older copy fails with ErrStaleVersion instead of silently
+// overwriting the change.  Without a VERSION field cmds are returned as
+// they are.
+func (r *Repository) versioned(model reflect.Type, key string, cmds [][]interface{}) [][]interface{} {
+	bump, ok := r.versionBump(model, key)
+	if !ok {
+		return cmds
+	}
+	return [][]interface{}{scriptWrite(key, false, append(slices.Clip(cmds), bump))}
+}
+
+// versionBump is the command incrementing the VERSION field of model (nil:
+// the ForModel type) at key, if it has one.
+func (r *Repository) versionBump(model reflect.Type, key string) ([]interface{}, bool) {
+	if model == nil {
+		model = reflect.TypeOf(r.model)
+	}
+	if model == nil {
+		return nil, false
+	}
+	f, ok := versionField(model)
+	if !ok {
+		return nil, false
+	}
+	if r.json {
+		return []interface{}{"JSON.NUMINCRBY", key, "$." + strings.Join(f.JSON, "."), 1}, true
+	}
+	return []interface{}{"HINCRBY", key, f.Name, 1}, true
+}
+
+// scriptWrite is the EVAL of versionedWrite running cmds on key.
+func scriptWrite(key string, mustExist bool, cmds [][]interface{}) []interface{} {
+	flag := "0"
+	if mustExist {
+		flag = "1"
+	}
+	cmd := []interface{}{"EVAL", versionedWrite, 1, key, flag, len(cmds)}
+	for _, c := range cmds {
+		cmd = append(cmd, len(c))
+		cmd = append(cmd, c...)
+	}
+	return cmd
+}
+
+// versionField returns the field of rt tagged VERSION, if any.
+func versionField(rt reflect.Type) (scan.Field, bool) {
+	for _, f := range scan.Fields(rt) {
+		if hasAttr(f.Attrs, "VERSION") {
+			return f, true
+		}
+	}
+	return scan.Field{}, false
+}
+
+// bumpVersion increments the VERSION field of entity, a pointer, and
+// returns the previous value with a func restoring it.
+func bumpVersion(entity any, f scan.Field) (int64, func(), error) {
+	rv := reflect.ValueOf(entity)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return 0, nil, fmt.Errorf("repository: saving a VERSION model needs a pointer, got %T", entity)
+	}
+	fv := rv.Elem().FieldByIndex(f.Index)
+	switch {
+	case fv.CanInt():
+		cur := fv.Int()
+		fv.SetInt(cur + 1)
+		return cur, func() { fv.SetInt(cur) }, nil
+	case fv.CanUint():
+		cur := fv.Uint()
+		fv.SetUint(cur + 1)
+		return int64(cur), func() { fv.SetUint(cur) }, nil
+	}
+	return 0, nil, fmt.Errorf("repository: VERSION field %s must be an integer, got %s", f.Struct.Name, fv.Type())
+}
+
+// versionCmd builds the EVAL that saves the entity – encoded as vals for a
+// hash, doc for JSON, with the bumped version – if the stored version is
+// still cur.
+func (r *Repository) versionCmd(key string, f scan.Field, cur int64, vals map[string]any, doc []byte) []interface{} {
+	if r.json {
+		return []interface{}{"EVAL", versionJSONScript, 1, key,
+			"$." + strings.Join(f.JSON, "."), strconv.FormatInt(cur, 10), string(doc)}
+	}
+	cmd := []interface{}{"EVAL", versionHashScript, 1, key, f.Name, strconv.FormatInt(cur, 10)}
+	for k, v := range vals {
+		cmd = append(cmd, k, v)
+	}
+	return cmd
+}
+
+// staleVersion maps the script's rejection onto ErrStaleVersion.
+func staleVersion(key string, err error) error {
+	if err != nil && strings.Contains(err.Error(), staleMarker) {
+		return fmt.Errorf("repository: %s: %w", key, ErrStaleVersion)
+	}
+	return err
+}
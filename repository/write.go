@@ -0,0 +1,292 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/manojoshi/redisorm/driver"
+	"github.com/manojoshi/redisorm/scan"
+)
+
+// WriteOpt tunes a single write (Repository.Save / Update / Delete, Repo.LoadHash,
+// LoadBulk, Delete).  Options are applied in order; later ones win.
+type WriteOpt func(*writeCfg)
+
+type writeCfg struct {
+	replicas    int           // WAIT numreplicas (0 = don't wait)
+	waitTimeout time.Duration // WAIT timeout (0 = block forever)
+
+	visibleIn      string        // FT index that must reflect the write
+	visibleTimeout time.Duration // give up polling after this long
+
+	seenFilter string                              // BF/CF key consulted before writing
+	seenCuckoo bool                                // seenFilter is a Cuckoo filter
+	seenID     func(key string, record any) string // identity checked; default the key
+
+	limiter *Limiter // token bucket consulted before the write is sent
+
+	ttl time.Duration // PEXPIRE sent with the write (0 = keep / no expiry)
+
+	chunk   int // LoadBulk records per pipeline
+	workers int // LoadBulk pipelines in flight
+}
+
+func newWriteCfg(opts []WriteOpt) *writeCfg {
+	cfg := &writeCfg{}
+	for _, o := range opts {
+		o(cfg)
+	}
+	return cfg
+}
+
+// WithReplicas makes the write block until at least n replicas acknowledged
+// it (Redis WAIT).  A zero timeout waits forever, exactly like WAIT itself.
+// WAIT must share the write's connection, so the executor has to be a
+// driver.Pipeliner; others fail with driver.ErrNoPipeline.
+// When fewer replicas answer in time the write is NOT rolled back; the call
+// returns a *ReplicationError so the caller can decide what to do.
+func WithReplicas(n int, timeout time.Duration) WriteOpt {
+	return func(c *writeCfg) { c.replicas, c.waitTimeout = n, timeout }
+}
+
+// WaitIndexed makes the write block until indexName reflects it: a saved key
+// is returned by FT.SEARCH, a deleted one no longer is.  Use it when the next
+// step immediately queries what was just written (read-your-writes).  The
+// index is polled with a short back-off for at most timeout; on expiry the
+// call returns an *IndexLagError (the write itself has succeeded).
+func WaitIndexed(indexName string, timeout time.Duration) WriteOpt {
+	return func(c *writeCfg) { c.visibleIn, c.visibleTimeout = indexName, timeout }
+}
+
+// SkipSeen makes Save / LoadHash / LoadBulk skip records whose key was already
+// written, as recorded in the Bloom filter at filterKey (RedisBloom; BF.ADD
+// creates it with default capacity, BF.RESERVE it yourself to size it).
+// Meant for streaming pipelines that replay events: duplicates are neither
+// rewritten nor re-indexed.  Bloom filters have false positives, so an unseen
+// record is very occasionally skipped; size the filter accordingly.
+func SkipSeen(filterKey string) WriteOpt {
+	return func(c *writeCfg) { c.seenFilter, c.seenCuckoo = filterKey, false }
+}
+
+// SkipSeenCuckoo is SkipSeen over a Cuckoo filter (CF.*), which supports
+// deletion: Delete removes the key from the filter too.
+func SkipSeenCuckoo(filterKey string) WriteOpt {
+	return func(c *writeCfg) { c.seenFilter, c.seenCuckoo = filterKey, true }
+}
+
+// SeenBy changes the identity SkipSeen checks from the key to fn's result,
+// e.g. an event ID that differs on every replay of the same document version.
+// Writes without a record (Delete, Update) keep using the key.
+func SeenBy(fn func(key string, record any) string) WriteOpt {
+	return func(c *writeCfg) { c.seenID = fn }
+}
+
+// WithTTL makes the written document expire after d: PEXPIRE is sent with
+// the write in one MULTI/EXEC transaction, so sessions, carts and locks
+// never exist without their expiry.  Without it a save leaves an existing
+// TTL as is.  The transaction needs a driver.Pipeliner executor; others fail
+// with driver.ErrNoPipeline.  LoadBulk applies it per chunk.
+func WithTTL(d time.Duration) WriteOpt {
+	return func(c *writeCfg) { c.ttl = d }
+}
+
+// ReplicationError reports a write that reached the primary but was not
+// acknowledged by the requested number of replicas.
+type ReplicationError struct {
+	Key  string
+	Want int // replicas requested
+	Got  int // replicas that acknowledged before the timeout
+}
+
+func (e *ReplicationError) Error() string {
+	return fmt.Sprintf("repository: %s acknowledged by %d/%d replicas", e.Key, e.Got, e.Want)
+}
+
+// IndexLagError reports a write that the index did not reflect in time.
+type IndexLagError struct {
+	Key     string
+	Index   string
+	Timeout time.Duration
+}
+
+func (e *IndexLagError) Error() string {
+	return fmt.Sprintf("repository: %s not reflected by %s after %s", e.Key, e.Index, e.Timeout)
+}
+
+// writeDoc sends cmds, the writes of one document stored at key, through
+// exec as a single pipeline and applies cfg around it.  WAIT is appended to
+// the same pipeline: it only covers writes issued on its own connection,
+// which is why it cannot be sent as a separate command through a pool, and
+// why WithReplicas fails with driver.ErrNoPipeline on executors that cannot
+// pipeline.  present says whether key should be searchable once cmds have
+// run; size feeds the byte budget of Throttle.
+func writeDoc(
+	ctx context.Context,
+	exec driver.Executor,
+	key string,
+	record any,
+	cfg *writeCfg,
+	present bool,
+	size int,
+	cmds ...[]interface{},
+) error {
+	if err := cfg.checkExec(exec, present); err != nil {
+		return err
+	}
+	id := key
+	if cfg.seenID != nil && record != nil { // deletes have no record to identify
+		id = cfg.seenID(key, record)
+	}
+	if present && cfg.seenFilter != "" {
+		seen, err := inFilter(ctx, exec, cfg, id)
+		if err != nil || seen {
+			return err
+		}
+	}
+	if cfg.limiter != nil { // after the seen check: skipped writes cost nothing
+		if err := cfg.limiter.Wait(ctx, 1, size); err != nil {
+			return err
+		}
+	}
+
+	if present && cfg.ttl > 0 {
+		cmds = append(cmds, []interface{}{"PEXPIRE", key, max(cfg.ttl.Milliseconds(), 1)})
+	}
+	res, err := cfg.send(ctx, exec, cmds, present)
+	if err != nil {
+		return err
+	}
+	for _, r := range res {
+		if err, ok := r.(error); ok {
+			return err
+		}
+	}
+	if cfg.replicas > 0 {
+		n, ok := res[len(res)-1].(int64)
+		if !ok {
+			return fmt.Errorf("repository: unexpected WAIT reply %T", res[len(res)-1])
+		}
+		if got := int(n); got < cfg.replicas {
+			return &ReplicationError{Key: key, Want: cfg.replicas, Got: got}
+		}
+	}
+
+	if cfg.seenFilter != "" {
+		if err := updateFilter(ctx, exec, cfg, id, present); err != nil {
+			return err
+		}
+	}
+	if cfg.visibleIn != "" {
+		return awaitIndexed(ctx, exec, key, cfg, present)
+	}
+	return nil
+}
+
+// checkExec rejects options exec cannot honour.
+func (c *writeCfg) checkExec(exec driver.Executor, present bool) error {
+	if _, ok := exec.(driver.Pipeliner); ok {
+		return nil
+	}
+	switch {
+	case c.replicas > 0:
+		return fmt.Errorf("repository: WithReplicas: %w", driver.ErrNoPipeline)
+	case present && c.ttl > 0:
+		return fmt.Errorf("repository: WithTTL: %w", driver.ErrNoPipeline)
+	}
+	return nil
+}
+
+// send sends the writes cmds, in a transaction when they carry a TTL, and
+// then WAIT if asked; its reply comes last.
+func (c *writeCfg) send(ctx context.Context, exec driver.Executor, cmds [][]interface{}, present bool) ([]any, error) {
+	var wait [][]interface{}
+	if c.replicas > 0 {
+		wait = append(wait, []interface{}{"WAIT", c.replicas, c.waitTimeout.Milliseconds()})
+	}
+	if present && c.ttl > 0 {
+		return driver.DoTx(ctx, exec, cmds, wait...)
+	}
+	return driver.DoBatch(ctx, exec, append(cmds, wait...))
+}
+
+// inFilter reports whether id is in the SkipSeen filter.  Records are only
+// added once written (updateFilter), so a failed write is retried.
+func inFilter(ctx context.Context, exec driver.Executor, cfg *writeCfg, id string) (bool, error) {
+	cmd := "BF.EXISTS"
+	if cfg.seenCuckoo {
+		cmd = "CF.EXISTS"
+	}
+	args := []interface{}{cmd, cfg.seenFilter, id}
+	res, err := exec.Do(ctx, args...)
+	if err != nil {
+		return false, driver.Wrap(args, err)
+	}
+	switch v := res.(type) {
+	case int64: // RESP-2
+		return v == 1, nil
+	case bool: // RESP-3
+		return v, nil
+	}
+	return false, fmt.Errorf("repository: unexpected %s reply %T", cmd, res)
+}
+
+// updateFilter records a write in the SkipSeen filter, or forgets a delete
+// when the filter is a Cuckoo filter (Bloom filters cannot delete).
+func updateFilter(ctx context.Context, exec driver.Executor, cfg *writeCfg, id string, present bool) error {
+	var args []interface{}
+	switch {
+	case present && cfg.seenCuckoo:
+		args = []interface{}{"CF.ADD", cfg.seenFilter, id}
+	case present:
+		args = []interface{}{"BF.ADD", cfg.seenFilter, id}
+	case cfg.seenCuckoo:
+		args = []interface{}{"CF.DEL", cfg.seenFilter, id}
+	default:
+		return nil
+	}
+	_, err := exec.Do(ctx, args...)
+	return driver.Wrap(args, err)
+}
+
+// hset builds HSET key f1 v1 f2 v2 ….
+func hset(key string, vals map[string]any) []interface{} {
+	cmd := make([]interface{}, 0, 2+2*len(vals))
+	cmd = append(cmd, "HSET", key)
+	for f, v := range vals {
+		cmd = append(cmd, f, v)
+	}
+	return cmd
+}
+
+// awaitIndexed polls FT.SEARCH … INKEYS key until the key's presence in the
+// index matches present, or cfg.visibleTimeout elapses.
+func awaitIndexed(ctx context.Context, exec driver.Executor, key string, cfg *writeCfg, present bool) error {
+	deadline := time.Now().Add(cfg.visibleTimeout)
+	backoff := time.Millisecond
+	for {
+		args := []interface{}{"FT.SEARCH", cfg.visibleIn, "*", "INKEYS", 1, key, "NOCONTENT", "LIMIT", 0, 0}
+		resp, err := exec.Do(ctx, args...)
+		if err != nil {
+			return driver.Wrap(args, err)
+		}
+		n, err := scan.Total(resp)
+		if err != nil {
+			return err
+		}
+		if (n > 0) == present {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return &IndexLagError{Key: key, Index: cfg.visibleIn, Timeout: cfg.visibleTimeout}
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		if backoff < 50*time.Millisecond {
+			backoff *= 2
+		}
+	}
+}
@@ -0,0 +1,331 @@
+package scan
+
+import (
+	"encoding"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FieldCodec converts a struct field to and from the string stored in Redis.
+// Register one for types the built-in kinds (string, ints, floats, bool)
+// don't cover; the same codec is used on write (repository) and on decode.
+type FieldCodec interface {
+	Encode(v reflect.Value) (string, error)
+	Decode(s string, dst reflect.Value) error
+}
+
+// NumericCodec is implemented by codecs whose stored form is not a plain
+// number RediSearch can index (big.Rat's "1/3").  Fields tagged SHADOW get
+// the float64 approximation written to a NUMERIC "<name>_num" sibling that
+// carries the index and SORTABLE, while the exact string stays untouched.
+type NumericCodec interface {
+	FieldCodec
+	Float64(v reflect.Value) (float64, error)
+}
+
+var codecs sync.Map // reflect.Type → FieldCodec
+
+// RegisterCodec installs c for values of type t (and *t).  Register before
+// the first decode of any struct using t: field metadata is cached.
+func RegisterCodec(t reflect.Type, c FieldCodec) { codecs.Store(t, c) }
+
+// CodecFor returns the codec for t: a registered one, else a built-in for
+// math/big types, else a TextMarshaler-based codec when t implements
+// encoding.TextMarshaler and TextUnmarshaler (decimal libraries do).
+func CodecFor(t reflect.Type) (FieldCodec, bool) {
+	base := t
+	if base.Kind() == reflect.Pointer {
+		base = base.Elem()
+	}
+	if c, ok := codecs.Load(base); ok {
+		return c.(FieldCodec), true
+	}
+	ptr := reflect.PointerTo(base)
+	if ptr.Implements(textMarshalerType) && ptr.Implements(textUnmarshalerType) {
+		return textCodec{}, true
+	}
+	return nil, false
+}
+
+// CodecForField is CodecFor honouring the field's redisorm tag: time.Time
+// fields are stored as unix seconds (the default, or explicitly
+// `redisorm:"@created_at,NUMERIC,SORTABLE,unix"`), or milliseconds with the
+// UNIXMS attribute (`redisorm:"@created_at,NUMERIC,SORTABLE,unixms"`), []string
+// fields as one TAG value joined by TagSeparator, and float slices and
+// arrays tagged VECTOR as the little-endian blob RediSearch indexes: 4
+// bytes per element, 8 for float64 elements or TYPE=FLOAT64.
+func CodecForField(f reflect.StructField) (FieldCodec, bool) {
+	base := f.Type
+	if base.Kind() == reflect.Pointer {
+		base = base.Elem()
+	}
+	if base == stringsType {
+		return listCodec{TagSeparator(f.Tag.Get("redisorm"))}, true
+	}
+	if c, ok := vectorCodecFor(base, f.Tag.Get("redisorm")); ok {
+		return c, true
+	}
+	if base == timeType {
+		for _, a := range strings.Split(f.Tag.Get("redisorm"), ",")[1:] {
+			if strings.EqualFold(a, "UNIXMS") {
+				return timeCodec{milli: true}, true
+			}
+		}
+	}
+	return CodecFor(f.Type)
+}
+
+// TagSeparator is the separator of a multi-value TAG field: the tag's
+// SEPARATOR=x attribute (`redisorm:"@labels,TAG,SEPARATOR=;"`), else ",",
+// RediSearch's default.
+func TagSeparator(tag string) string {
+	for _, a := range strings.Split(tag, ",")[1:] {
+		if k, v, ok := strings.Cut(a, "="); ok && strings.EqualFold(k, "SEPARATOR") && v != "" {
+			return v[:1]
+		}
+	}
+	return ","
+}
+
+var (
+	stringsType         = reflect.TypeOf([]string(nil))
+	timeType            = reflect.TypeOf(time.Time{})
+	textMarshalerType   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+func init() {
+	RegisterCodec(reflect.TypeOf(big.Int{}), textCodec{})
+	RegisterCodec(reflect.TypeOf(big.Float{}), textCodec{})
+	RegisterCodec(reflect.TypeOf(big.Rat{}), ratCodec{})
+	RegisterCodec(timeType, timeCodec{})
+}
+
+// textCodec round-trips through MarshalText / UnmarshalText.  Both T and *T
+// fields are handled; a nil *T encodes as "".
+type textCodec struct{}
+
+func (textCodec) Encode(v reflect.Value) (string, error) {
+	p, ok := addr(v)
+	if !ok {
+		return "", nil
+	}
+	b, err := p.Interface().(encoding.TextMarshaler).MarshalText()
+	return string(b), err
+}
+
+func (textCodec) Decode(s string, dst reflect.Value) error {
+	if s == "" {
+		return nil
+	}
+	return alloc(dst).Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s))
+}
+
+func (c textCodec) Float64(v reflect.Value) (float64, error) {
+	s, err := c.Encode(v)
+	if err != nil {
+		return 0, err
+	}
+	f, _, err := big.ParseFloat(s, 10, 64, big.ToNearestEven)
+	if err != nil {
+		return 0, err
+	}
+	out, _ := f.Float64()
+	return out, nil
+}
+
+// ratCodec stores big.Rat as the exact "a/b" string and exposes a float
+// approximation for SHADOW fields.
+type ratCodec struct{ textCodec }
+
+func (ratCodec) Float64(v reflect.Value) (float64, error) {
+	p, ok := addr(v)
+	if !ok {
+		return 0, nil
+	}
+	f, _ := p.Interface().(*big.Rat).Float64()
+	return f, nil
+}
+
+// addr returns a pointer to v's value, or false for a nil pointer field.
+func addr(v reflect.Value) (reflect.Value, bool) {
+	if v.Kind() == reflect.Pointer {
+		return v, !v.IsNil()
+	}
+	if v.CanAddr() {
+		return v.Addr(), true
+	}
+	cp := reflect.New(v.Type())
+	cp.Elem().Set(v)
+	return cp, true
+}
+
+// alloc returns a settable pointer for dst, allocating nil pointer fields.
+func alloc(dst reflect.Value) reflect.Value {
+	if dst.Kind() == reflect.Pointer {
+		if dst.IsNil() {
+			dst.Set(reflect.New(dst.Type().Elem()))
+		}
+		return dst
+	}
+	return dst.Addr()
+}
+
+// timeCodec stores time.Time as unix seconds (milli: milliseconds) so it
+// can be indexed NUMERIC and range-queried.  The zero time is stored as 0
+// and read back as the zero time; RFC 3339 text written before is still
+// decoded.
+type timeCodec struct{ milli bool }
+
+func (c timeCodec) Encode(v reflect.Value) (string, error) {
+	p, ok := addr(v)
+	if !ok {
+		return "", nil
+	}
+	return strconv.FormatInt(c.unix(*p.Interface().(*time.Time)), 10), nil
+}
+
+func (c timeCodec) Decode(s string, dst reflect.Value) error {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil
+	}
+	t := alloc(dst).Interface().(*time.Time)
+	n, err := strconv.ParseInt(s, 10, 64)
+	switch {
+	case err != nil:
+		return t.UnmarshalText([]byte(s))
+	case n == 0:
+		*t = time.Time{}
+	case c.milli:
+		*t = time.UnixMilli(n)
+	default:
+		*t = time.Unix(n, 0)
+	}
+	return nil
+}
+
+func (c timeCodec) Float64(v reflect.Value) (float64, error) {
+	p, ok := addr(v)
+	if !ok {
+		return 0, nil
+	}
+	return float64(c.unix(*p.Interface().(*time.Time))), nil
+}
+
+func (c timeCodec) unix(t time.Time) int64 {
+	switch {
+	case t.IsZero():
+		return 0
+	case c.milli:
+		return t.UnixMilli()
+	}
+	return t.Unix()
+}
+
+// listCodec stores []string as one sep-joined TAG value.  Empty elements
+// are dropped on decode, as RediSearch drops them when indexing.
+type listCodec struct{ sep string }
+
+func (c listCodec) Encode(v reflect.Value) (string, error) {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return "", nil
+		}
+		v = v.Elem()
+	}
+	return strings.Join(v.Interface().([]string), c.sep), nil
+}
+
+func (c listCodec) Decode(s string, dst reflect.Value) error {
+	var out []string
+	for _, e := range strings.Split(s, c.sep) {
+		if e = strings.TrimSpace(e); e != "" {
+			out = append(out, e)
+		}
+	}
+	alloc(dst).Elem().Set(reflect.ValueOf(out))
+	return nil
+}
+
+// vectorCodecFor returns the blob codec of a VECTOR field of type t.
+func vectorCodecFor(t reflect.Type, tag string) (vectorCodec, bool) {
+	if t.Kind() != reflect.Slice && t.Kind() != reflect.Array {
+		return vectorCodec{}, false
+	}
+	ek := t.Elem().Kind()
+	if ek != reflect.Float32 && ek != reflect.Float64 {
+		return vectorCodec{}, false
+	}
+	vector, width := false, 4
+	if ek == reflect.Float64 {
+		width = 8
+	}
+	for _, a := range strings.Split(tag, ",")[1:] {
+		k, v, _ := strings.Cut(a, "=")
+		switch {
+		case strings.EqualFold(k, "VECTOR"):
+			vector = true
+		case strings.EqualFold(k, "TYPE") && strings.EqualFold(v, "FLOAT64"):
+			width = 8
+		case strings.EqualFold(k, "TYPE") && strings.EqualFold(v, "FLOAT32"):
+			width = 4
+		}
+	}
+	return vectorCodec{width}, vector
+}
+
+// vectorCodec stores a float slice or array as little-endian IEEE-754
+// values of width bytes each, the encoding of q.VectorBlob.
+type vectorCodec struct{ width int }
+
+func (c vectorCodec) Encode(v reflect.Value) (string, error) {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return "", nil
+		}
+		v = v.Elem()
+	}
+	b := make([]byte, c.width*v.Len())
+	for i := 0; i < v.Len(); i++ {
+		x := v.Index(i).Float()
+		if c.width == 8 {
+			binary.LittleEndian.PutUint64(b[8*i:], math.Float64bits(x))
+		} else {
+			binary.LittleEndian.PutUint32(b[4*i:], math.Float32bits(float32(x)))
+		}
+	}
+	return string(b), nil
+}
+
+func (c vectorCodec) Decode(s string, dst reflect.Value) error {
+	if len(s)%c.width != 0 {
+		return fmt.Errorf("vector blob of %d bytes is not a multiple of %d", len(s), c.width)
+	}
+	n := len(s) / c.width
+	v := alloc(dst).Elem()
+	switch {
+	case v.Kind() == reflect.Slice:
+		v.Set(reflect.MakeSlice(v.Type(), n, n))
+	case n != v.Len():
+		return fmt.Errorf("vector of %d elements does not fit %s", n, v.Type())
+	}
+	b := []byte(s)
+	for i := 0; i < n; i++ {
+		var x float64
+		if c.width == 8 {
+			x = math.Float64frombits(binary.LittleEndian.Uint64(b[8*i:]))
+		} else {
+			x = float64(math.Float32frombits(binary.LittleEndian.Uint32(b[4*i:])))
+		}
+		v.Index(i).SetFloat(x)
+	}
+	return nil
+}
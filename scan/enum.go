@@ -0,0 +1,87 @@
+package scan
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+
+	"golang.org/x/exp/constraints"
+)
+
+// RegisterEnum maps an integer-backed Go enum to stable string tags.  Fields
+// of type E are written as their name, decoded back to the constant, and
+// query values of type E (q.Eq("status", StatusPending)) compile to the name
+// too — so TAG fields never see the raw integer.
+//
+//	type Status int
+//	const (
+//	    StatusPending Status = iota + 1
+//	    StatusShipped
+//	)
+//	func init() {
+//	    scan.RegisterEnum(map[Status]string{
+//	        StatusPending: "PENDING",
+//	        StatusShipped: "SHIPPED",
+//	    })
+//	}
+//
+// Renumbering the constants is then safe; renaming the strings is not.
+func RegisterEnum[E constraints.Integer](names map[E]string) {
+	c := enumCodec{
+		typ:    reflect.TypeOf(E(0)),
+		byVal:  make(map[int64]string, len(names)),
+		byName: make(map[string]int64, len(names)),
+	}
+	for v, name := range names {
+		c.byVal[int64(v)] = name
+		c.byName[name] = int64(v)
+	}
+	RegisterCodec(c.typ, c)
+}
+
+type enumCodec struct {
+	typ    reflect.Type
+	byVal  map[int64]string
+	byName map[string]int64
+}
+
+func (c enumCodec) Encode(v reflect.Value) (string, error) {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return "", nil
+		}
+		v = v.Elem()
+	}
+	n := intOf(v)
+	if name, ok := c.byVal[n]; ok {
+		return name, nil
+	}
+	return "", fmt.Errorf("scan: %s(%d) has no registered name", c.typ, n)
+}
+
+func (c enumCodec) Decode(s string, dst reflect.Value) error {
+	n, ok := c.byName[s]
+	if !ok {
+		return fmt.Errorf("scan: %q is not a known %s", s, c.typ)
+	}
+	if dst.Kind() == reflect.Pointer {
+		dst = alloc(dst).Elem()
+	}
+	if dst.CanInt() {
+		dst.SetInt(n)
+	} else {
+		dst.SetUint(uint64(n))
+	}
+	return nil
+}
+
+func intOf(v reflect.Value) int64 {
+	if v.CanInt() {
+		return v.Int()
+	}
+	if v.CanUint() {
+		return int64(v.Uint())
+	}
+	n, _ := strconv.ParseInt(fmt.Sprint(v.Interface()), 10, 64)
+	return n
+}
@@ -0,0 +1,87 @@
+package scan
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Field is one redisorm-tagged field of a model, found by Fields.
+type Field struct {
+	Struct reflect.StructField
+	Index  []int    // path for reflect.Value.FieldByIndex
+	Name   string   // stored / indexed name: "created_ts", "audit_created_ts"
+	Attrs  []string // tag attributes after the name: "NUMERIC", "SORTABLE", …
+	Tag    string   // the full redisorm tag, with Name in place of its name
+	JSON   []string // JSON document path: ["audit", "created_ts"]
+}
+
+var fieldCache sync.Map // reflect.Type → []Field
+
+// Fields lists the tagged fields of the struct type rt in declaration
+// order, descending into struct fragments shared between models:
+//
+//   - an embedded struct without a tag contributes its fields as they are
+//     (type Order struct{ Audit; … } has "created_ts");
+//   - a struct-typed field tagged with a name and no codec is nested: its
+//     fields are named "<name>_<field>" and, for JSON documents, live in an
+//     object under <name> (`redisorm:"@audit"` has "audit_created_ts").
+//
+// Pointer-to-struct fragments are not descended into.
+func Fields(rt reflect.Type) []Field {
+	if rt.Kind() == reflect.Pointer {
+		rt = rt.Elem()
+	}
+	if rt.Kind() != reflect.Struct {
+		return nil
+	}
+	if fs, ok := fieldCache.Load(rt); ok {
+		return fs.([]Field)
+	}
+	fs := collectFields(rt, nil, "", nil)
+	fieldCache.Store(rt, fs)
+	return fs
+}
+
+func collectFields(rt reflect.Type, index []int, prefix string, path []string) []Field {
+	var out []Field
+	for i := 0; i < rt.NumField(); i++ {
+		sf := rt.Field(i)
+		idx := append(append([]int{}, index...), i)
+		tag := sf.Tag.Get("redisorm")
+		fragment := sf.Type.Kind() == reflect.Struct && sf.Type != timeType
+		if fragment {
+			if _, coded := CodecFor(sf.Type); coded {
+				fragment = false
+			}
+		}
+		switch {
+		case tag == "" && sf.Anonymous && fragment:
+			out = append(out, collectFields(sf.Type, idx, prefix, path)...)
+			continue
+		case tag == "" || tag == "-":
+			continue
+		}
+		parts := strings.Split(tag, ",")
+		name := strings.TrimPrefix(parts[0], "@")
+		if fragment && len(parts) == 1 {
+			out = append(out, collectFields(sf.Type, idx, prefix+name+"_", append(slices.Clone(path), name))...)
+			continue
+		}
+		full := prefix + name
+		if MetaField(name) {
+			full = name // reply metadata is never nested
+		}
+		parts[0] = full
+		out = append(out, Field{
+			Struct: sf,
+			Index:  idx,
+			Name:   full,
+			Attrs:  parts[1:],
+			Tag:    strings.Join(parts, ","),
+			JSON:   append(slices.Clone(path), name),
+		})
+	}
+	return out
+}
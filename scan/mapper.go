@@ -24,12 +24,12 @@ func DecodeSlice[T any](raw any) ([]T, error) {
 	if err != nil {
 		return nil, err
 	}
-	total, hits, err := extractHits(reply)
+	_, hits, err := extractHits(reply)
 	if err != nil {
 		return nil, err
 	}
 
-	out := make([]T, total)
+	out := make([]T, len(hits))
 	for i, kv := range hits {
 		m, err := toStrMap(kv)
 		if err != nil {
@@ -48,12 +48,12 @@ func DecodeMaps(raw any) ([]map[string]string, error) {
 	if err != nil {
 		return nil, err
 	}
-	total, hits, err := extractHits(reply)
+	_, hits, err := extractHits(reply)
 	if err != nil {
 		return nil, err
 	}
 
-	out := make([]map[string]string, total)
+	out := make([]map[string]string, len(hits))
 	for i, kv := range hits {
 		m, err := toStrMap(kv)
 		if err != nil {
@@ -64,6 +64,212 @@ func DecodeMaps(raw any) ([]map[string]string, error) {
 	return out, nil
 }
 
+// Reply metadata decoded into tagged struct fields by Docs: a string field
+// tagged `redisorm:"__key"` receives the Redis key, a float64 one tagged
+// `redisorm:"__score"` the relevance score (SearchBuilder.WithScores).
+// They are not document fields and are never stored or indexed.
+const (
+	KeyField   = "__key"
+	ScoreField = "__score"
+)
+
+// MetaField reports whether a tag name is KeyField or ScoreField.
+func MetaField(name string) bool { return name == KeyField || name == ScoreField }
+
+// Hit is a single FT.SEARCH document together with its Redis key and, when
+// the query ran WITHSCORES, its relevance score.
+type Hit struct {
+	Key    string
+	Score  float64
+	Fields map[string]string
+}
+
+// DecodeHits decodes an FT.SEARCH reply keeping document keys and scores.
+// withScores must match whether the query was sent WITHSCORES, because the
+// RESP-2 reply layout differs ([n, id, score, kv, …] vs [n, id, kv, …]).
+func DecodeHits(raw any, withScores bool) ([]Hit, error) {
+	reply, err := normalize(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	// RESP-3: results is a list of {id, score, extra_attributes} maps
+	if top, ok := reply.(map[string]interface{}); ok {
+		resultsRaw, ok := top["results"].([]interface{})
+		if !ok {
+			return nil, errors.New("scan: missing results array")
+		}
+		out := make([]Hit, len(resultsRaw))
+		for i, r := range resultsRaw {
+			hit, err := toAnyMap(r)
+			if err != nil {
+				return nil, err
+			}
+			out[i].Key = toStr(hit["id"])
+			if sc, ok := hit["score"]; ok {
+				out[i].Score, _ = strconv.ParseFloat(toStr(sc), 64)
+			}
+			if ea, ok := hit["extra_attributes"]; ok {
+				if out[i].Fields, err = toStrMap(ea); err != nil {
+					return nil, err
+				}
+			} else {
+				out[i].Fields = map[string]string{}
+			}
+		}
+		return out, nil
+	}
+
+	arr, ok := reply.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("scan: unrecognised reply %T", reply)
+	}
+	if len(arr) == 0 {
+		return nil, nil
+	}
+	stride := 2
+	if withScores {
+		stride = 3
+	}
+	out := make([]Hit, 0, (len(arr)-1)/stride)
+	for i := 1; i+stride-1 < len(arr); i += stride {
+		h := Hit{Key: toStr(arr[i])}
+		if withScores {
+			h.Score, _ = strconv.ParseFloat(toStr(arr[i+1]), 64)
+		}
+		if h.Fields, err = toStrMap(arr[i+stride-1]); err != nil {
+			return nil, err
+		}
+		out = append(out, h)
+	}
+	return out, nil
+}
+
+// DecodeKeys decodes an FT.SEARCH … NOCONTENT reply into the matching
+// document keys.
+func DecodeKeys(raw any) ([]string, error) {
+	hits, err := DecodeKeyHits(raw, false)
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]string, len(hits))
+	for i, h := range hits {
+		keys[i] = h.Key
+	}
+	return keys, nil
+}
+
+// DecodeKeyHits is DecodeHits for NOCONTENT replies, which carry no field
+// payloads: each Hit has its Key, Score (withScores) and empty Fields.
+func DecodeKeyHits(raw any, withScores bool) ([]Hit, error) {
+	reply, err := normalize(raw)
+	if err != nil {
+		return nil, err
+	}
+	if top, ok := reply.(map[string]interface{}); ok {
+		resultsRaw, ok := top["results"].([]interface{})
+		if !ok {
+			return nil, errors.New("scan: missing results array")
+		}
+		out := make([]Hit, len(resultsRaw))
+		for i, r := range resultsRaw {
+			hit, err := toAnyMap(r)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = Hit{Key: toStr(hit["id"]), Fields: map[string]string{}}
+			if sc, ok := hit["score"]; ok {
+				out[i].Score, _ = strconv.ParseFloat(toStr(sc), 64)
+			}
+		}
+		return out, nil
+	}
+
+	arr, ok := reply.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("scan: unrecognised reply %T", reply)
+	}
+	stride := 1
+	if withScores {
+		stride = 2
+	}
+	out := make([]Hit, 0, max(len(arr)-1, 0)/stride)
+	for i := 1; i+stride-1 < len(arr); i += stride {
+		h := Hit{Key: toStr(arr[i]), Fields: map[string]string{}}
+		if withScores {
+			h.Score, _ = strconv.ParseFloat(toStr(arr[i+1]), 64)
+		}
+		out = append(out, h)
+	}
+	return out, nil
+}
+
+// Result is a decoded FT.SEARCH reply: the page of hits plus the number of
+// documents matching overall, for "page 3 of 57".
+type Result struct {
+	Total int
+	Hits  []Hit
+}
+
+// DecodeResult decodes an FT.SEARCH reply keeping the total; withScores is
+// as for DecodeHits.
+func DecodeResult(raw any, withScores bool) (Result, error) {
+	total, err := Total(raw)
+	if err != nil {
+		return Result{}, err
+	}
+	hits, err := DecodeHits(raw, withScores)
+	if err != nil {
+		return Result{}, err
+	}
+	return Result{Total: total, Hits: hits}, nil
+}
+
+// Total returns the total_results counter of an FT.SEARCH reply without
+// decoding any documents.  Handy with LIMIT 0 0 / NOCONTENT queries.
+func Total(raw any) (int, error) {
+	reply, err := normalize(raw)
+	if err != nil {
+		return 0, err
+	}
+	switch v := reply.(type) {
+	case map[string]interface{}:
+		if n, ok := toInt64(v["total_results"]); ok {
+			return int(n), nil
+		}
+		return 0, errors.New("scan: missing total_results")
+	case []interface{}:
+		if len(v) == 0 {
+			return 0, nil
+		}
+		if n, ok := toInt64(v[0]); ok {
+			return int(n), nil
+		}
+		return 0, errors.New("scan: first array element is not int64")
+	}
+	return 0, fmt.Errorf("scan: unrecognised reply %T", reply)
+}
+
+// Warnings returns the "warning" entries of a RESP-3 FT.SEARCH / FT.AGGREGATE
+// reply, e.g. "Max prefix expansions limit was reached".  RESP-2 replies
+// carry no warnings.
+func Warnings(raw any) []string {
+	reply, err := normalize(raw)
+	if err != nil {
+		return nil
+	}
+	top, ok := reply.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	list, _ := top["warning"].([]interface{})
+	out := make([]string, 0, len(list))
+	for _, w := range list {
+		out = append(out, toStr(w))
+	}
+	return out
+}
+
 /*───────────────────────────────
 |  Top-level normalisation       |
 └───────────────────────────────*/
@@ -92,7 +298,8 @@ func normalize(raw any) (any, error) {
 |  Extract document hits         |
 └───────────────────────────────*/
 
-// Returns: totalResults, sliceOfHits, error.
+// Returns: totalResults, sliceOfHits, error.  totalResults counts every
+// match, not just the hits in this reply.
 func extractHits(reply any) (int, []any, error) {
 	// RESP-3: top-level map
 	if top, ok := reply.(map[string]interface{}); ok {
@@ -125,13 +332,9 @@ func extractHits(reply any) (int, []any, error) {
 		}
 
 		total := len(hits)
-		/*
-			if tv, ok := top["total_results"]; ok {
-				if n, ok := toInt64(tv); ok {
-					total = int(n)
-				}
-			}
-		*/
+		if n, ok := toInt64(top["total_results"]); ok {
+			total = int(n)
+		}
 		return total, hits, nil
 	}
 
@@ -147,38 +350,58 @@ func extractHits(reply any) (int, []any, error) {
 	if !ok {
 		return 0, nil, errors.New("scan: first array element is not int64")
 	}
-	total := int(count)
-	hits := make([]any, total)
-	for i := 0; i < total; i++ {
-		hits[i] = arr[i*2+2] // skip doc-id elements
+	// FT.AGGREGATE: [total, row, row, …]; FT.SEARCH: [total, id, row, id, row, …]
+	if len(arr) > 1 {
+		if _, rows := arr[1].([]interface{}); rows {
+			return int(count), arr[1:], nil
+		}
+	}
+	hits := make([]any, 0, len(arr)/2)
+	for i := 2; i < len(arr); i += 2 {
+		hits = append(hits, arr[i]) // skip doc-id elements
 	}
-	return total, hits, nil
+	return int(count), hits, nil
 }
 
 /*───────────────────────────────
 |  KV payload → map              |
 └───────────────────────────────*/
 
+func toAnyMap(v any) (map[string]interface{}, error) {
+	switch h := v.(type) {
+	case map[string]interface{}:
+		return h, nil
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(h))
+		for k, val := range h {
+			m[toStr(k)] = val
+		}
+		return m, nil
+	default:
+		return nil, fmt.Errorf("scan: unknown hit type %T", v)
+	}
+}
+
 func toStrMap(v any) (map[string]string, error) {
 	switch t := v.(type) {
 	case []interface{}: // RESP-2 KV list
 		m := make(map[string]string, len(t)/2)
 		for i := 0; i+1 < len(t); i += 2 {
-			m[toStr(t[i])] = toStr(t[i+1])
+			m[toStr(t[i])] = rawStr(t[i+1])
 		}
 		return m, nil
 
 	case map[interface{}]interface{}: // RESP-3 extra_attributes
 		m := make(map[string]string, len(t))
 		for k, v := range t {
-			m[toStr(k)] = toStr(v)
+			m[toStr(k)] = rawStr(v)
 		}
 		return m, nil
 
 	case map[string]interface{}:
 		m := make(map[string]string, len(t))
 		for k, v := range t {
-			m[k] = toStr(v)
+			m[k] = rawStr(v)
 		}
 		return m, nil
 
@@ -197,6 +420,7 @@ type fieldMeta struct {
 	name  string
 	index []int
 	kind  reflect.Kind
+	codec FieldCodec // nil for built-in kinds
 }
 
 func assign[T any](ptr *T, kv map[string]string) error {
@@ -207,8 +431,70 @@ func assign[T any](ptr *T, kv map[string]string) error {
 		return nil
 	}
 
-	val := reflect.ValueOf(ptr).Elem()
+	return assignValue(reflect.ValueOf(ptr).Elem(), kv)
+}
+
+// Into decodes one field map (an HGETALL reply, a Hit's Fields, …) into the
+// struct dst points to.
+func Into(dst any, kv map[string]string) error {
+	rv := reflect.ValueOf(dst)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("scan: Into needs a non-nil pointer, got %T", dst)
+	}
+	if m, ok := dst.(*map[string]string); ok {
+		*m = kv
+		return nil
+	}
+	return assignValue(rv.Elem(), kv)
+}
+
+// HashFields decodes an HGETALL reply (RESP-2 list or RESP-3 map).
+func HashFields(raw any) (map[string]string, error) { return toStrMap(raw) }
+
+// Docs decodes the Fields of every hit into T, and the hit's key and score
+// into fields tagged KeyField / ScoreField.
+func Docs[T any](hits []Hit) ([]T, error) {
+	out := make([]T, len(hits))
+	for i, h := range hits {
+		if err := assign(&out[i], h.Fields); err != nil {
+			return nil, err
+		}
+		assignMeta(reflect.ValueOf(&out[i]).Elem(), h)
+	}
+	return out, nil
+}
+
+// assignMeta sets the KeyField / ScoreField fields of a struct value.
+func assignMeta(val reflect.Value, h Hit) {
+	if val.Kind() == reflect.Pointer {
+		if val.IsNil() {
+			return
+		}
+		val = val.Elem()
+	}
+	if val.Kind() != reflect.Struct {
+		return
+	}
+	metaAny, _ := metaCache.Load(val.Type())
+	if metaAny == nil {
+		return // never decoded into, so it has no fields at all
+	}
+	for _, fm := range metaAny.([]fieldMeta) {
+		f := val.FieldByIndex(fm.index)
+		switch {
+		case fm.name == KeyField && fm.kind == reflect.String:
+			f.SetString(h.Key)
+		case fm.name == ScoreField && (fm.kind == reflect.Float64 || fm.kind == reflect.Float32):
+			f.SetFloat(h.Score)
+		}
+	}
+}
+
+func assignValue(val reflect.Value, kv map[string]string) error {
 	rt := val.Type()
+	if rt.Kind() != reflect.Struct {
+		return fmt.Errorf("scan: cannot decode into %s", rt)
+	}
 
 	metaAny, _ := metaCache.Load(rt)
 	if metaAny == nil {
@@ -218,6 +504,12 @@ func assign[T any](ptr *T, kv map[string]string) error {
 	for _, fm := range metaAny.([]fieldMeta) {
 		if s, ok := kv[fm.name]; ok {
 			f := val.FieldByIndex(fm.index)
+			if fm.codec != nil {
+				if err := fm.codec.Decode(s, f); err != nil {
+					return fmt.Errorf("scan: field %q: %w", fm.name, err)
+				}
+				continue
+			}
 			switch fm.kind {
 			case reflect.String:
 				f.SetString(s)
@@ -238,15 +530,11 @@ func assign[T any](ptr *T, kv map[string]string) error {
 }
 
 func buildMeta(rt reflect.Type) []fieldMeta {
-	out := make([]fieldMeta, 0, rt.NumField())
-	for i := 0; i < rt.NumField(); i++ {
-		f := rt.Field(i)
-		tag := f.Tag.Get("redisorm")
-		if tag == "" {
-			continue
-		}
-		name := strings.TrimPrefix(strings.Split(tag, ",")[0], "@")
-		out = append(out, fieldMeta{name, f.Index, f.Type.Kind()})
+	fs := Fields(rt)
+	out := make([]fieldMeta, 0, len(fs))
+	for _, f := range fs {
+		codec, _ := CodecForField(f.Struct)
+		out = append(out, fieldMeta{f.Name, f.Index, f.Struct.Type.Kind(), codec})
 	}
 	return out
 }
@@ -255,6 +543,18 @@ func buildMeta(rt reflect.Type) []fieldMeta {
 |  Small util fns                |
 └───────────────────────────────*/
 
+// rawStr is toStr for field values: strings are kept byte for byte, so
+// binary values such as vector blobs survive.
+func rawStr(v interface{}) string {
+	switch t := v.(type) {
+	case string:
+		return t
+	case []byte:
+		return string(t)
+	}
+	return toStr(v)
+}
+
 func toStr(v interface{}) string {
 	switch t := v.(type) {
 	case string:
@@ -0,0 +1,106 @@
+package scan
+
+import "fmt"
+
+// Pivot helpers reshape the flat rows of a multi-key GROUPBY into nested
+// maps.  Values are decoded into V exactly like DecodeSlice does (struct with
+// redisorm tags, or map[string]string):
+//
+//	type Stats struct {
+//	    Orders   int     `redisorm:"orders"`
+//	    TotalQty int     `redisorm:"total_qty"`
+//	    AvgQty   float64 `redisorm:"avg_qty"`
+//	}
+//	rows, _ := repo.Aggregate(ctx, q.MatchAll(),
+//	    repository.Group(q.By("warehouse_id"), q.By("status")), …)
+//	byWH, _ := scan.Pivot2[Stats](scan.Maps(rows), "warehouse_id", "status")
+//	fmt.Println(byWH["3"]["PENDING"].TotalQty)
+//
+// Rows missing a key field are an error; a repeated key combination keeps
+// the last row.
+
+// Pivot indexes rows by a single group field.
+func Pivot[V any](rows []map[string]string, key string) (map[string]V, error) {
+	out := make(map[string]V, len(rows))
+	for i, row := range rows {
+		k, err := pivotKey(row, key, i)
+		if err != nil {
+			return nil, err
+		}
+		var v V
+		if err := assign(&v, row); err != nil {
+			return nil, err
+		}
+		out[k] = v
+	}
+	return out, nil
+}
+
+// Pivot2 nests rows as m[k1][k2].
+func Pivot2[V any](rows []map[string]string, k1, k2 string) (map[string]map[string]V, error) {
+	out := make(map[string]map[string]V)
+	for i, row := range rows {
+		a, err := pivotKey(row, k1, i)
+		if err != nil {
+			return nil, err
+		}
+		b, err := pivotKey(row, k2, i)
+		if err != nil {
+			return nil, err
+		}
+		var v V
+		if err := assign(&v, row); err != nil {
+			return nil, err
+		}
+		inner, ok := out[a]
+		if !ok {
+			inner = make(map[string]V)
+			out[a] = inner
+		}
+		inner[b] = v
+	}
+	return out, nil
+}
+
+// Pivot3 nests rows as m[k1][k2][k3].
+func Pivot3[V any](rows []map[string]string, k1, k2, k3 string) (map[string]map[string]map[string]V, error) {
+	out := make(map[string]map[string]map[string]V)
+	for i, row := range rows {
+		a, err := pivotKey(row, k1, i)
+		if err != nil {
+			return nil, err
+		}
+		b, err := pivotKey(row, k2, i)
+		if err != nil {
+			return nil, err
+		}
+		c, err := pivotKey(row, k3, i)
+		if err != nil {
+			return nil, err
+		}
+		var v V
+		if err := assign(&v, row); err != nil {
+			return nil, err
+		}
+		mid, ok := out[a]
+		if !ok {
+			mid = make(map[string]map[string]V)
+			out[a] = mid
+		}
+		inner, ok := mid[b]
+		if !ok {
+			inner = make(map[string]V)
+			mid[b] = inner
+		}
+		inner[c] = v
+	}
+	return out, nil
+}
+
+func pivotKey(row map[string]string, key string, i int) (string, error) {
+	k, ok := row[key]
+	if !ok {
+		return "", fmt.Errorf("scan: row %d has no group field %q", i, key)
+	}
+	return k, nil
+}
@@ -0,0 +1,79 @@
+package scan
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// TypeMap decodes heterogeneous documents — event streams, "search
+// everything" indexes — into different concrete types chosen by a
+// discriminator field.
+//
+//	events := scan.NewTypeMap("type").
+//	    Register("order", OrderEvent{}).
+//	    Register("refund", RefundEvent{})
+//	docs, err := events.DecodeHits(hits)
+//	for _, d := range docs {
+//	    switch e := d.(type) {
+//	    case *OrderEvent:  …
+//	    case *RefundEvent: …
+//	    }
+//	}
+//
+// Each decoded value is a pointer to a fresh instance of the registered type.
+type TypeMap struct {
+	field    string
+	types    map[string]reflect.Type
+	fallback bool
+}
+
+// NewTypeMap starts a map keyed on the discriminator field.
+func NewTypeMap(field string) *TypeMap {
+	return &TypeMap{field: field, types: make(map[string]reflect.Type)}
+}
+
+// Register binds discriminator value to the type of prototype (a struct or
+// pointer to one).
+func (m *TypeMap) Register(value string, prototype any) *TypeMap {
+	t := reflect.TypeOf(prototype)
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	m.types[value] = t
+	return m
+}
+
+// FallbackToMap decodes documents with an unknown or missing discriminator
+// as map[string]string instead of failing.
+func (m *TypeMap) FallbackToMap() *TypeMap { m.fallback = true; return m }
+
+// Decode picks the concrete type for one document and decodes into it.
+func (m *TypeMap) Decode(kv map[string]string) (any, error) {
+	disc, ok := kv[m.field]
+	t, known := m.types[disc]
+	if !ok || !known {
+		if m.fallback {
+			return kv, nil
+		}
+		return nil, fmt.Errorf("scan: no type registered for %s=%q", m.field, disc)
+	}
+	ptr := reflect.New(t)
+	if err := assignValue(ptr.Elem(), kv); err != nil {
+		return nil, err
+	}
+	return ptr.Interface(), nil
+}
+
+// DecodeHits decodes every hit, preserving order.
+func (m *TypeMap) DecodeHits(hits []Hit) ([]any, error) {
+	out := make([]any, len(hits))
+	for i, h := range hits {
+		v, err := m.Decode(h.Fields)
+		if err != nil {
+			return nil, err
+		}
+		assignMeta(reflect.ValueOf(v), h)
+		out[i] = v
+	}
+	return out, nil
+}
@@ -0,0 +1,171 @@
+package scan
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// AggRow wraps one FT.AGGREGATE row with typed accessors.  Conversion
+// failures don't panic or vanish: they are collected and reported by Err, so
+// a loop can read every column and check once.
+//
+//	for _, row := range scan.AggRows(rows) {
+//	    wh := row.Get("warehouse_id").String()
+//	    qty := row.Get("total_qty").Int64()
+//	    avg := row.Get("avg_qty").Float()
+//	    if err := row.Err(); err != nil {
+//	        return err
+//	    }
+//	    …
+//	}
+type AggRow struct {
+	Fields map[string]string
+	errs   []error
+}
+
+// NewAggRow wraps a single decoded row.
+func NewAggRow(m map[string]string) *AggRow { return &AggRow{Fields: m} }
+
+// AggRows wraps every row returned by Aggregate / DecodeMaps.
+func AggRows(rows []map[string]string) []*AggRow {
+	out := make([]*AggRow, len(rows))
+	for i, m := range rows {
+		out[i] = NewAggRow(m)
+	}
+	return out
+}
+
+// Get returns an accessor for field.  A missing field is only an error once
+// a typed conversion is requested.
+func (r *AggRow) Get(field string) Value {
+	s, ok := r.Fields[strings.TrimPrefix(field, "@")]
+	return Value{row: r, field: field, raw: s, ok: ok}
+}
+
+// Err reports every conversion failure seen so far (nil if none).
+func (r *AggRow) Err() error { return errors.Join(r.errs...) }
+
+// Value is a single column of an AggRow.
+type Value struct {
+	row   *AggRow
+	field string
+	raw   string
+	ok    bool
+}
+
+// Exists reports whether the column was present in the row.
+func (v Value) Exists() bool { return v.ok }
+
+// String returns the raw value ("" if missing; never an error).
+func (v Value) String() string { return v.raw }
+
+// Int64 parses the value as an integer.  Reducer output such as "12.0" or
+// "1.7e+06" is accepted as long as it is integral.
+func (v Value) Int64() int64 {
+	if !v.present() {
+		return 0
+	}
+	if n, err := strconv.ParseInt(v.raw, 10, 64); err == nil {
+		return n
+	}
+	f, err := strconv.ParseFloat(v.raw, 64)
+	if err != nil || f != math.Trunc(f) {
+		v.fail("integer")
+		return 0
+	}
+	return int64(f)
+}
+
+// Float parses the value as a float64.
+func (v Value) Float() float64 {
+	if !v.present() {
+		return 0
+	}
+	f, err := strconv.ParseFloat(v.raw, 64)
+	if err != nil {
+		v.fail("float")
+	}
+	return f
+}
+
+// Bool accepts "1"/"0" and anything strconv.ParseBool understands.
+func (v Value) Bool() bool {
+	if !v.present() {
+		return false
+	}
+	b, err := strconv.ParseBool(v.raw)
+	if err != nil {
+		v.fail("bool")
+	}
+	return b
+}
+
+// Time interprets the value as unix seconds (fractions allowed).
+func (v Value) Time() time.Time {
+	if !v.present() {
+		return time.Time{}
+	}
+	f, err := strconv.ParseFloat(v.raw, 64)
+	if err != nil {
+		v.fail("unix time")
+		return time.Time{}
+	}
+	sec, frac := math.Modf(f)
+	return time.Unix(int64(sec), int64(frac*1e9))
+}
+
+func (v Value) present() bool {
+	if !v.ok {
+		v.row.errs = append(v.row.errs, fmt.Errorf("scan: field %q missing", v.field))
+	}
+	return v.ok
+}
+
+func (v Value) fail(kind string) {
+	v.row.errs = append(v.row.errs, fmt.Errorf("scan: field %q: %q is not a valid %s", v.field, v.raw, kind))
+}
+
+// Doc is a search hit or aggregate row with the AggRow accessors flattened
+// to one call per field.  The map-based Repository.Search / Aggregate return
+// Docs; conversion failures collect in Err just like on AggRow.
+//
+//	for _, d := range docs {
+//	    qty, due := d.Int("qty"), d.Time("promise_ts")
+//	    if err := d.Err(); err != nil {
+//	        return err
+//	    }
+//	    …
+//	}
+type Doc struct{ AggRow }
+
+// NewDoc wraps a single decoded row.
+func NewDoc(m map[string]string) *Doc { return &Doc{AggRow{Fields: m}} }
+
+// ToDocs wraps every row of DecodeMaps.
+func ToDocs(rows []map[string]string) []*Doc {
+	out := make([]*Doc, len(rows))
+	for i, m := range rows {
+		out[i] = NewDoc(m)
+	}
+	return out
+}
+
+// Maps unwraps docs, e.g. for Pivot.
+func Maps(docs []*Doc) []map[string]string {
+	out := make([]map[string]string, len(docs))
+	for i, d := range docs {
+		out[i] = d.Fields
+	}
+	return out
+}
+
+func (d *Doc) String(field string) string  { return d.Get(field).String() }
+func (d *Doc) Int(field string) int64      { return d.Get(field).Int64() }
+func (d *Doc) Float(field string) float64  { return d.Get(field).Float() }
+func (d *Doc) Bool(field string) bool      { return d.Get(field).Bool() }
+func (d *Doc) Time(field string) time.Time { return d.Get(field).Time() }
+func (d *Doc) Has(field string) bool       { return d.Get(field).Exists() }
@@ -0,0 +1,352 @@
+// Package suggest wraps RediSearch's autocomplete dictionaries
+// (FT.SUGADD / FT.SUGGET / FT.SUGDEL / FT.SUGLEN).
+//
+//	d := suggest.New(conn, "ac:products")
+//	_ = d.Add(ctx, "laptop stand", 3)
+//	hits, _ := d.Get(ctx, "lap", suggest.Max(5), suggest.Fuzzy(), suggest.WithScores())
+//
+// Suggester offers the same with fixed arguments and payloads.
+//
+// FT.SUGGET reports scores normalised against the match, so Dict also keeps
+// each term's real weight in a hash at "<key>:weights" for Stats and Prune.
+// Entries added to the dictionary by other clients have no weight there.
+package suggest
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/manojoshi/redisorm/driver"
+)
+
+// Dict is one suggestion dictionary.
+type Dict struct {
+	exec    driver.Executor
+	key     string
+	weights string // hash of term → weight as given to Add
+}
+
+// New binds a dictionary key.  The dictionary is created by the first Add.
+func New(exec driver.Executor, key string) *Dict {
+	return &Dict{exec: exec, key: key, weights: key + ":weights"}
+}
+
+// Suggestion is one FT.SUGGET result.  Score is only set with WithScores,
+// Payload only with WithPayloads; FT.SUGGET normalises it, so it only ranks
+// the suggestions of one Get.
+type Suggestion struct {
+	Term    string
+	Score   float64
+	Payload string
+}
+
+// ------------------------------------------------------------------
+// Options
+// ------------------------------------------------------------------
+
+// AddOpt tunes Add.
+type AddOpt func(*addCfg)
+
+type addCfg struct {
+	incr    bool
+	payload string
+}
+
+// Incr adds score to the entry's current score instead of replacing it.
+func Incr() AddOpt { return func(c *addCfg) { c.incr = true } }
+
+// Payload attaches an opaque string returned by WithPayloads.
+func Payload(p string) AddOpt { return func(c *addCfg) { c.payload = p } }
+
+// GetOpt tunes Get.
+type GetOpt func(*getCfg)
+
+type getCfg struct {
+	max          int
+	fuzzy        bool
+	withScores   bool
+	withPayloads bool
+}
+
+// Max caps the number of suggestions (server default 5).
+func Max(n int) GetOpt { return func(c *getCfg) { c.max = n } }
+
+// Fuzzy matches prefixes within Levenshtein distance 1.
+func Fuzzy() GetOpt { return func(c *getCfg) { c.fuzzy = true } }
+
+// WithScores fills Suggestion.Score.
+func WithScores() GetOpt { return func(c *getCfg) { c.withScores = true } }
+
+// WithPayloads fills Suggestion.Payload.
+func WithPayloads() GetOpt { return func(c *getCfg) { c.withPayloads = true } }
+
+// ------------------------------------------------------------------
+// Commands
+// ------------------------------------------------------------------
+
+// Add inserts term with score, returning the dictionary size afterwards.
+// The weight hash is updated alongside.
+func (d *Dict) Add(ctx context.Context, term string, score float64, opts ...AddOpt) (int, error) {
+	cfg := &addCfg{}
+	for _, o := range opts {
+		o(cfg)
+	}
+	args := []interface{}{"FT.SUGADD", d.key, term, score}
+	weight := []interface{}{"HSET", d.weights, term, score}
+	if cfg.incr {
+		args = append(args, "INCR")
+		weight = []interface{}{"HINCRBYFLOAT", d.weights, term, score}
+	}
+	if cfg.payload != "" {
+		args = append(args, "PAYLOAD", cfg.payload)
+	}
+	res, err := driver.DoBatch(ctx, d.exec, [][]interface{}{args, weight})
+	if err != nil {
+		return 0, err
+	}
+	for _, r := range res {
+		if err, ok := r.(error); ok {
+			return 0, err
+		}
+	}
+	return toInt(res[0])
+}
+
+// Get returns suggestions for prefix, best first.
+func (d *Dict) Get(ctx context.Context, prefix string, opts ...GetOpt) ([]Suggestion, error) {
+	cfg := &getCfg{}
+	for _, o := range opts {
+		o(cfg)
+	}
+	args := []interface{}{"FT.SUGGET", d.key, prefix}
+	if cfg.fuzzy {
+		args = append(args, "FUZZY")
+	}
+	if cfg.withScores {
+		args = append(args, "WITHSCORES")
+	}
+	if cfg.withPayloads {
+		args = append(args, "WITHPAYLOADS")
+	}
+	if cfg.max > 0 {
+		args = append(args, "MAX", cfg.max)
+	}
+	raw, err := d.exec.Do(ctx, args...)
+	if err != nil {
+		return nil, driver.Wrap(args, err)
+	}
+	if raw == nil {
+		return nil, nil
+	}
+	flat, ok := raw.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("suggest: unexpected FT.SUGGET reply %T", raw)
+	}
+
+	stride := 1
+	if cfg.withScores {
+		stride++
+	}
+	if cfg.withPayloads {
+		stride++
+	}
+	out := make([]Suggestion, 0, len(flat)/stride)
+	for i := 0; i+stride <= len(flat); i += stride {
+		s := Suggestion{Term: str(flat[i])}
+		j := i + 1
+		if cfg.withScores {
+			if s.Score, err = strconv.ParseFloat(str(flat[j]), 64); err != nil {
+				return nil, fmt.Errorf("suggest: score of %q: %w", s.Term, err)
+			}
+			j++
+		}
+		if cfg.withPayloads {
+			s.Payload = str(flat[j])
+		}
+		out = append(out, s)
+	}
+	return out, nil
+}
+
+// Del removes term and its weight, reporting whether it was present.
+func (d *Dict) Del(ctx context.Context, term string) (bool, error) {
+	res, err := driver.DoBatch(ctx, d.exec, d.delCmds(term))
+	if err != nil {
+		return false, err
+	}
+	for _, r := range res {
+		if err, ok := r.(error); ok {
+			return false, err
+		}
+	}
+	n, err := toInt(res[0])
+	return n > 0, err
+}
+
+func (d *Dict) delCmds(term string) [][]interface{} {
+	return [][]interface{}{
+		{"FT.SUGDEL", d.key, term},
+		{"HDEL", d.weights, term},
+	}
+}
+
+// Len returns the number of entries (FT.SUGLEN).
+func (d *Dict) Len(ctx context.Context) (int, error) {
+	args := []interface{}{"FT.SUGLEN", d.key}
+	raw, err := d.exec.Do(ctx, args...)
+	if err != nil {
+		return 0, driver.Wrap(args, err)
+	}
+	return toInt(raw)
+}
+
+// ------------------------------------------------------------------
+// Maintenance
+// ------------------------------------------------------------------
+
+// Stats summarises the entries reachable from a set of prefixes.
+type Stats struct {
+	Size     int     // FT.SUGLEN, the whole dictionary
+	Sampled  int     // distinct weighted entries seen under the prefixes
+	MinScore float64 // weights as given to Add, over the sampled entries
+	MaxScore float64
+	AvgScore float64
+}
+
+// Stats reads the dictionary size and score distribution.  Dictionaries
+// cannot be enumerated, so scores are sampled through FT.SUGGET on each of
+// prefixes, at most perPrefix entries each, and weighed from the weight
+// hash; entries without a weight are left out.
+func (d *Dict) Stats(ctx context.Context, prefixes []string, perPrefix int) (Stats, error) {
+	var st Stats
+	n, err := d.Len(ctx)
+	if err != nil {
+		return st, err
+	}
+	st.Size = n
+	entries, err := d.sample(ctx, prefixes, perPrefix)
+	if err != nil {
+		return st, err
+	}
+	var sum float64
+	for _, s := range entries {
+		if st.Sampled == 0 || s.Score < st.MinScore {
+			st.MinScore = s.Score
+		}
+		if s.Score > st.MaxScore {
+			st.MaxScore = s.Score
+		}
+		sum += s.Score
+		st.Sampled++
+	}
+	if st.Sampled > 0 {
+		st.AvgScore = sum / float64(st.Sampled)
+	}
+	return st, nil
+}
+
+// Prune deletes entries weighing less than minScore among those reachable
+// from prefixes (see Stats for why prefixes are needed; "a".."z" plus digits
+// covers most dictionaries).  Entries without a weight are kept.  It returns
+// how many entries were removed.
+func (d *Dict) Prune(ctx context.Context, minScore float64, prefixes []string, perPrefix int) (int, error) {
+	entries, err := d.sample(ctx, prefixes, perPrefix)
+	if err != nil {
+		return 0, err
+	}
+	var cmds [][]interface{}
+	for _, s := range entries {
+		if s.Score < minScore {
+			cmds = append(cmds, d.delCmds(s.Term)...)
+		}
+	}
+	if len(cmds) == 0 {
+		return 0, nil
+	}
+	res, err := driver.DoBatch(ctx, d.exec, cmds)
+	if err != nil {
+		return 0, err
+	}
+	removed := 0
+	for i, r := range res {
+		if err, ok := r.(error); ok {
+			return removed, err
+		}
+		if n, _ := toInt(r); i%2 == 0 && n > 0 { // FT.SUGDEL replies
+			removed++
+		}
+	}
+	return removed, nil
+}
+
+// sample collects distinct entries under prefixes, scored with their weight.
+func (d *Dict) sample(ctx context.Context, prefixes []string, perPrefix int) (map[string]Suggestion, error) {
+	var terms []interface{}
+	seen := map[string]bool{}
+	for _, p := range prefixes {
+		hits, err := d.Get(ctx, p, Max(perPrefix))
+		if err != nil {
+			return nil, err
+		}
+		for _, h := range hits {
+			if !seen[h.Term] {
+				seen[h.Term] = true
+				terms = append(terms, h.Term)
+			}
+		}
+	}
+	out := map[string]Suggestion{}
+	if len(terms) == 0 {
+		return out, nil
+	}
+	args := append([]interface{}{"HMGET", d.weights}, terms...)
+	raw, err := d.exec.Do(ctx, args...)
+	if err != nil {
+		return nil, driver.Wrap(args, err)
+	}
+	weights, ok := raw.([]interface{})
+	if !ok || len(weights) != len(terms) {
+		return nil, fmt.Errorf("suggest: unexpected HMGET reply %T", raw)
+	}
+	for i, w := range weights {
+		if w == nil {
+			continue
+		}
+		term := terms[i].(string)
+		score, err := strconv.ParseFloat(str(w), 64)
+		if err != nil {
+			return nil, fmt.Errorf("suggest: weight of %q: %w", term, err)
+		}
+		out[term] = Suggestion{Term: term, Score: score}
+	}
+	return out, nil
+}
+
+// ------------------------------------------------------------------
+// helpers
+// ------------------------------------------------------------------
+
+func toInt(v any) (int, error) {
+	switch t := v.(type) {
+	case int64:
+		return int(t), nil
+	case int:
+		return t, nil
+	case string:
+		return strconv.Atoi(t)
+	}
+	return 0, fmt.Errorf("suggest: unexpected integer reply %T", v)
+}
+
+func str(v any) string {
+	switch t := v.(type) {
+	case nil: // WITHPAYLOADS entry added without one
+		return ""
+	case string:
+		return t
+	case []byte:
+		return string(t)
+	}
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,48 @@
+package suggest
+
+import (
+	"context"
+
+	"github.com/manojoshi/redisorm/driver"
+)
+
+// Suggester is the fixed-argument form of Dict for the common case of an
+// autocomplete box next to a search index: every Get returns scores and
+// payloads (a product ID, a URL, …) with the terms.
+//
+//	s := suggest.NewSuggester(conn, "ac:products")
+//	_, _ = s.Add(ctx, "laptop stand", 3, "sku:1042")
+//	hits, _ := s.Get(ctx, "lap", true, 5)
+type Suggester struct {
+	dict *Dict
+}
+
+// NewSuggester binds the dictionary at key.
+func NewSuggester(exec driver.Executor, key string) *Suggester {
+	return &Suggester{dict: New(exec, key)}
+}
+
+// Dict returns the underlying dictionary, for INCR adds and maintenance.
+func (s *Suggester) Dict() *Dict { return s.dict }
+
+// Add inserts term with score and payload ("" for none), returning the
+// dictionary size afterwards.  An existing term's score is replaced.
+func (s *Suggester) Add(ctx context.Context, term string, score float64, payload string) (int, error) {
+	return s.dict.Add(ctx, term, score, Payload(payload))
+}
+
+// Get returns up to limit suggestions for prefix (limit <= 0: the server's
+// default of 5), best first, with Score and Payload set.  fuzzy also
+// matches prefixes within Levenshtein distance 1.
+func (s *Suggester) Get(ctx context.Context, prefix string, fuzzy bool, limit int) ([]Suggestion, error) {
+	opts := []GetOpt{WithScores(), WithPayloads(), Max(limit)}
+	if fuzzy {
+		opts = append(opts, Fuzzy())
+	}
+	return s.dict.Get(ctx, prefix, opts...)
+}
+
+// Del removes term, reporting whether it was present.
+func (s *Suggester) Del(ctx context.Context, term string) (bool, error) {
+	return s.dict.Del(ctx, term)
+}